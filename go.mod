@@ -3,6 +3,7 @@ module Hermes
 go 1.25.1
 
 require (
+	github.com/golang-jwt/jwt/v5 v5.2.2
 	github.com/jackc/pgx/v5 v5.8.0
 	github.com/lib/pq v1.11.1
 	github.com/stretchr/testify v1.11.1
@@ -1,9 +1,12 @@
 package app
 
 import (
+	"Hermes/internal/auth"
 	"Hermes/internal/config"
+	"Hermes/internal/filter"
 	"Hermes/internal/handler"
 	"Hermes/internal/logger"
+	"Hermes/internal/ratelimit"
 	"Hermes/internal/repository"
 	"Hermes/internal/server"
 	"Hermes/internal/service"
@@ -23,6 +26,8 @@ type App struct {
 	ctx     context.Context
 	cancel  context.CancelFunc
 	storage repository.Storage
+	service service.Service
+	archive config.Archive
 }
 
 func Boot() *App {
@@ -56,8 +61,11 @@ func wireApp(db *dbpg.DB, logger logger.Logger, logFile *os.File, config config.
 
 	ctx, cancel := newContext(logger)
 	storge := repository.NewStorage(logger, config.Storage, db)
-	service := service.NewService(logger, storge)
-	handler := handler.NewHandler(service)
+	service := service.NewService(logger, config.Comments, storge, newFilters(logger, config.Comments.Filters, storge))
+	handler, err := handler.NewHandler(service, newAuthenticator(logger, config.Auth), ratelimit.NewLimiter(), config)
+	if err != nil {
+		logger.LogFatal("app — failed to create handler", err, "layer", "app")
+	}
 	server := server.NewServer(logger, config.Server, handler)
 
 	return &App{
@@ -67,10 +75,39 @@ func wireApp(db *dbpg.DB, logger logger.Logger, logFile *os.File, config config.
 		ctx:     ctx,
 		cancel:  cancel,
 		storage: storge,
+		service: service,
+		archive: config.Comments.Archive,
 	}
 
 }
 
+func newAuthenticator(logger logger.Logger, config config.Auth) auth.Authenticator {
+
+	if !config.Enabled {
+		logger.LogInfo("app — authentication is disabled", "layer", "app")
+		return nil
+	}
+
+	authenticator, err := auth.NewAuthenticator(config)
+	if err != nil {
+		logger.LogFatal("app — failed to create authenticator", err, "layer", "app")
+	}
+
+	return authenticator
+
+}
+
+func newFilters(logger logger.Logger, config config.Filters, storage repository.Storage) []filter.ContentFilter {
+
+	filters, err := filter.New(config, storage)
+	if err != nil {
+		logger.LogFatal("app — failed to create content filters", err, "layer", "app")
+	}
+
+	return filters
+
+}
+
 func newContext(logger logger.Logger) (context.Context, context.CancelFunc) {
 
 	sigCh := make(chan os.Signal, 1)
@@ -95,6 +132,8 @@ func (a *App) Run() {
 		}
 	}()
 
+	go a.runArchiver()
+
 	<-a.ctx.Done()
 
 	a.Stop()
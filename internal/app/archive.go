@@ -0,0 +1,39 @@
+package app
+
+import (
+	"time"
+)
+
+const defaultArchiveInterval = time.Hour
+
+// runArchiver locks inactive threads once at startup and then on every tick
+// until the app stops. Failures are logged by the service and retried on the
+// next tick.
+func (a *App) runArchiver() {
+
+	if a.archive.InactiveDays <= 0 {
+		return
+	}
+
+	interval := a.archive.Interval
+	if interval <= 0 {
+		interval = defaultArchiveInterval
+	}
+
+	a.logger.LogInfo("app — thread archiving enabled", "inactive_days", a.archive.InactiveDays, "interval", interval.String(), "layer", "app")
+
+	_, _ = a.service.ArchiveThreads(a.ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.ctx.Done():
+			return
+		case <-ticker.C:
+			_, _ = a.service.ArchiveThreads(a.ctx)
+		}
+	}
+
+}
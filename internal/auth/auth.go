@@ -0,0 +1,35 @@
+package auth
+
+import (
+	"Hermes/internal/auth/jwt"
+	"Hermes/internal/config"
+	"Hermes/internal/models"
+	"context"
+)
+
+// Authenticator verifies a bearer token and returns the identity it was issued to.
+type Authenticator interface {
+	Authenticate(token string) (models.Identity, error)
+}
+
+// NewAuthenticator creates a JWT Authenticator for the configured algorithm and keys.
+func NewAuthenticator(config config.Auth) (Authenticator, error) {
+	authenticator, err := jwt.NewAuthenticator(config)
+	if err != nil {
+		return nil, err
+	}
+	return authenticator, nil
+}
+
+type identityKey struct{}
+
+// WithIdentity returns a copy of ctx carrying the authenticated caller.
+func WithIdentity(ctx context.Context, identity models.Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns the authenticated caller stored in ctx, if any.
+func IdentityFromContext(ctx context.Context) (models.Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(models.Identity)
+	return identity, ok
+}
@@ -0,0 +1,86 @@
+package jwt
+
+import (
+	"Hermes/internal/config"
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	algorithmHS256 = "HS256"
+	algorithmRS256 = "RS256"
+)
+
+// claims are the registered JWT claims plus the caller's roles.
+type claims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles,omitempty"`
+}
+
+type Authenticator struct {
+	key    any
+	parser *jwt.Parser
+}
+
+// NewAuthenticator loads the verification key for config.Algorithm: the shared
+// secret for HS256 or the PEM public key at config.PublicKeyPath for RS256.
+func NewAuthenticator(config config.Auth) (*Authenticator, error) {
+
+	var key any
+
+	switch config.Algorithm {
+	case algorithmHS256:
+		if config.Secret == "" {
+			return nil, fmt.Errorf("auth: HS256 requires a secret")
+		}
+		key = []byte(config.Secret)
+
+	case algorithmRS256:
+		pem, err := os.ReadFile(config.PublicKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("auth: failed to read public key: %w", err)
+		}
+		key, err = jwt.ParseRSAPublicKeyFromPEM(pem)
+		if err != nil {
+			return nil, fmt.Errorf("auth: failed to parse public key: %w", err)
+		}
+
+	default:
+		return nil, fmt.Errorf("auth: unsupported algorithm %q", config.Algorithm)
+	}
+
+	options := []jwt.ParserOption{jwt.WithValidMethods([]string{config.Algorithm})}
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+
+	return &Authenticator{key: key, parser: jwt.NewParser(options...)}, nil
+
+}
+
+// Authenticate validates the token's signature and registered claims and
+// returns its subject and roles. Tokens without a subject are rejected.
+func (a *Authenticator) Authenticate(token string) (models.Identity, error) {
+
+	var claims claims
+
+	if _, err := a.parser.ParseWithClaims(token, &claims, func(*jwt.Token) (any, error) {
+		return a.key, nil
+	}); err != nil {
+		return models.Identity{}, fmt.Errorf("%w: %v", errs.ErrUnauthorized, err)
+	}
+
+	if claims.Subject == "" {
+		return models.Identity{}, fmt.Errorf("%w: token has no subject", errs.ErrUnauthorized)
+	}
+
+	return models.Identity{Subject: claims.Subject, Roles: claims.Roles}, nil
+
+}
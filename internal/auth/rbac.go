@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"Hermes/internal/models"
+	"slices"
+)
+
+type Permission string
+
+const (
+	PermCreate     Permission = "create"      // post, edit and delete own comments, vote and react
+	PermDeleteAny  Permission = "delete-any"  // edit or delete anyone's comment
+	PermModerate   Permission = "moderate"    // approve and reject queued comments
+	PermLockThread Permission = "lock-thread" // lock and unlock threads
+	PermBanUser    Permission = "ban-user"    // ban users from commenting
+	PermExport     Permission = "export"      // export comment data
+	PermManageKeys Permission = "manage-keys" // issue, list and revoke API keys
+)
+
+const (
+	RoleReader    = "reader"
+	RoleCommenter = "commenter"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// permissions is the permission table of every role. Roles not listed here
+// grant nothing.
+var permissions = map[string][]Permission{
+	RoleReader:    {},
+	RoleCommenter: {PermCreate},
+	RoleModerator: {PermCreate, PermDeleteAny, PermModerate, PermLockThread},
+	RoleAdmin:     {PermCreate, PermDeleteAny, PermModerate, PermLockThread, PermBanUser, PermExport, PermManageKeys},
+}
+
+// IsPermission reports whether permission is granted by any role, which makes
+// it a valid API key scope.
+func IsPermission(permission string) bool {
+	return slices.Contains(permissions[RoleAdmin], Permission(permission))
+}
+
+// IsRole reports whether role is one of the known roles.
+func IsRole(role string) bool {
+	_, ok := permissions[role]
+	return ok
+}
+
+// Can reports whether any of identity's roles or scopes grants permission.
+func Can(identity models.Identity, permission Permission) bool {
+	if slices.Contains(identity.Scopes, string(permission)) {
+		return true
+	}
+	for _, role := range identity.Roles {
+		if slices.Contains(permissions[role], permission) {
+			return true
+		}
+	}
+	return false
+}
@@ -9,9 +9,11 @@ import (
 )
 
 type Config struct {
-	Logger  Logger  `mapstructure:"logger"`
-	Server  Server  `mapstructure:"server"`
-	Storage Storage `mapstructure:"database"`
+	Logger   Logger   `mapstructure:"logger"`
+	Server   Server   `mapstructure:"server"`
+	Storage  Storage  `mapstructure:"database"`
+	Comments Comments `mapstructure:"comments"`
+	Auth     Auth     `mapstructure:"auth"`
 }
 
 type Logger struct {
@@ -20,11 +22,24 @@ type Logger struct {
 }
 
 type Server struct {
-	Port            string        `mapstructure:"port"`
-	ReadTimeout     time.Duration `mapstructure:"read_timeout"`
-	WriteTimeout    time.Duration `mapstructure:"write_timeout"`
-	MaxHeaderBytes  int           `mapstructure:"max_header_bytes"`
-	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"`
+	Port                string        `mapstructure:"port"`
+	ReadTimeout         time.Duration `mapstructure:"read_timeout"`
+	WriteTimeout        time.Duration `mapstructure:"write_timeout"`
+	MaxHeaderBytes      int           `mapstructure:"max_header_bytes"`
+	ShutdownTimeout     time.Duration `mapstructure:"shutdown_timeout"`
+	RateLimits          []RateLimit   `mapstructure:"rate_limits"`
+	CredentialRateLimit RateLimit     `mapstructure:"credential_rate_limit"`
+	TrustedProxies      []string      `mapstructure:"trusted_proxies"`
+}
+
+// RateLimit is a token bucket for one route: each client may make Burst
+// requests at once, refilled at Requests per Per. Burst defaults to Requests.
+type RateLimit struct {
+	Method   string        `mapstructure:"method"`
+	Path     string        `mapstructure:"path"`
+	Requests int           `mapstructure:"requests"`
+	Per      time.Duration `mapstructure:"per"`
+	Burst    int           `mapstructure:"burst"`
 }
 
 type Storage struct {
@@ -38,6 +53,74 @@ type Storage struct {
 	MaxIdleConns       int           `mapstructure:"max_idle_conns"`
 	ConnMaxLifetime    time.Duration `mapstructure:"conn_max_lifetime"`
 	QueryRetryStrategy RetryStrategy `mapstructure:"query_retry_strategy"`
+	SoftDelete         bool          `mapstructure:"soft_delete"`
+}
+
+// Comments configures comment handling. AuthorSecret signs the author
+// tokens that let anonymous authors edit and delete their own comments.
+type Comments struct {
+	Reactions       []string  `mapstructure:"reactions"`
+	PreApproval     bool      `mapstructure:"pre_approval"`
+	ReportThreshold int       `mapstructure:"report_threshold"`
+	Filters         Filters   `mapstructure:"filters"`
+	Challenge       Challenge `mapstructure:"challenge"`
+	Archive         Archive   `mapstructure:"archive"`
+	AuthorSecret    string    `mapstructure:"author_secret"`
+}
+
+// Archive configures the job that locks threads nobody has posted to or
+// edited in for InactiveDays. Zero days disables it.
+type Archive struct {
+	InactiveDays int           `mapstructure:"inactive_days"`
+	Interval     time.Duration `mapstructure:"interval"`
+}
+
+// Challenge configures the proof-of-work anonymous callers must solve to
+// post. Difficulty is the number of leading zero bits required; it grows by
+// one for every RateStep comments posted within RateWindow, up to
+// MaxDifficulty.
+type Challenge struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Secret        string        `mapstructure:"secret"`
+	TTL           time.Duration `mapstructure:"ttl"`
+	MinDifficulty int           `mapstructure:"min_difficulty"`
+	MaxDifficulty int           `mapstructure:"max_difficulty"`
+	RateWindow    time.Duration `mapstructure:"rate_window"`
+	RateStep      int           `mapstructure:"rate_step"`
+}
+
+// Filters configures the content filters run on new comments. Each action
+// is "reject", "flag" (hold for moderation) or "allow" (disabled).
+type Filters struct {
+	BannedWords   BannedWordsFilter `mapstructure:"banned_words"`
+	Links         LimitFilter       `mapstructure:"links"`
+	RepeatedChars LimitFilter       `mapstructure:"repeated_chars"`
+	Duplicates    DuplicatesFilter  `mapstructure:"duplicates"`
+}
+
+type BannedWordsFilter struct {
+	Action string   `mapstructure:"action"`
+	Words  []string `mapstructure:"words"`
+}
+
+type LimitFilter struct {
+	Action string `mapstructure:"action"`
+	Max    int    `mapstructure:"max"`
+}
+
+type DuplicatesFilter struct {
+	Action string        `mapstructure:"action"`
+	Window time.Duration `mapstructure:"window"`
+}
+
+type Auth struct {
+	Enabled             bool   `mapstructure:"enabled"`
+	AllowAnonymousReads bool   `mapstructure:"allow_anonymous_reads"`
+	Algorithm           string `mapstructure:"algorithm"`
+	Secret              string `mapstructure:"secret"`
+	PublicKeyPath       string `mapstructure:"public_key_path"`
+	Issuer              string `mapstructure:"issuer"`
+	Audience            string `mapstructure:"audience"`
 }
 
 type RetryStrategy struct {
@@ -66,6 +149,14 @@ func Load() (Config, error) {
 
 	loadEnvs(&conf)
 
+	if conf.Comments.Challenge.Enabled && conf.Comments.Challenge.Secret == "" {
+		return Config{}, fmt.Errorf("challenge: CHALLENGE_SECRET is not set")
+	}
+
+	if !conf.Auth.Enabled && conf.Comments.AuthorSecret == "" {
+		return Config{}, fmt.Errorf("comments: AUTHOR_SECRET is not set")
+	}
+
 	return conf, nil
 
 }
@@ -74,5 +165,8 @@ func loadEnvs(conf *Config) {
 
 	conf.Storage.Username = os.Getenv("DB_USER")
 	conf.Storage.Password = os.Getenv("DB_PASSWORD")
+	conf.Auth.Secret = os.Getenv("JWT_SECRET")
+	conf.Comments.Challenge.Secret = os.Getenv("CHALLENGE_SECRET")
+	conf.Comments.AuthorSecret = os.Getenv("AUTHOR_SECRET")
 
 }
@@ -15,4 +15,35 @@ var (
 	ErrInvalidPage      = errors.New("invalid page number")              // invalid page number
 	ErrInvalidLimit     = errors.New("invalid limit")                    // invalid limit
 	ErrInvalidSort      = errors.New("invalid sort value")               // invalid sort value
+	ErrInvalidCursor    = errors.New("invalid cursor")                   // invalid cursor
+	ErrEmptyQuery       = errors.New("search query can not be empty")    // search query can not be empty
+	ErrInvalidMaxDepth  = errors.New("invalid max depth")                // invalid max depth
+	ErrInvalidMaxChild  = errors.New("invalid max children")             // invalid max children
+	ErrInvalidInclude   = errors.New("invalid include value")            // invalid include value
+	ErrInvalidThreadKey = errors.New("thread key is too long")           // thread key is too long
+	ErrEmptyVoter       = errors.New("voter can not be empty")           // voter can not be empty
+	ErrInvalidVote      = errors.New("vote value must be -1, 0 or 1")    // vote value must be -1, 0 or 1
+	ErrEmptyReactor     = errors.New("reactor can not be empty")         // reactor can not be empty
+	ErrInvalidReaction  = errors.New("reaction is not allowed")          // reaction is not allowed
+	ErrUnauthorized     = errors.New("unauthorized")                     // missing or invalid credentials
+	ErrForbidden        = errors.New("forbidden")                        // caller lacks the permission
+	ErrUserNotFound     = errors.New("user not found")                   // user not found
+	ErrInvalidKeyName   = errors.New("invalid api key name")             // api key name is empty or too long
+	ErrInvalidScope     = errors.New("invalid api key scope")            // invalid api key scope
+	ErrAPIKeyNotFound   = errors.New("api key not found")                // api key not found
+	ErrKeyNameTaken     = errors.New("api key name already in use")      // another live key has the same name
+	ErrEmptyReporter    = errors.New("reporter can not be empty")        // reporter can not be empty
+	ErrInvalidReason    = errors.New("invalid report reason")            // invalid report reason
+	ErrNoteTooLong      = errors.New("report note is too long")          // report note is too long
+	ErrBannedWords      = errors.New("comment contains banned words")    // comment contains banned words
+	ErrTooManyLinks     = errors.New("comment contains too many links")  // comment contains too many links
+	ErrRepeatedChars    = errors.New("comment repeats characters")       // comment repeats a character too many times
+	ErrDuplicateComment = errors.New("duplicate comment")                // author posted the same text recently
+	ErrRateLimited      = errors.New("too many requests")                // client exceeded the route's rate limit
+	ErrProofRequired    = errors.New("proof of work required")           // anonymous caller sent no solved challenge
+	ErrInvalidProof     = errors.New("invalid proof of work")            // challenge is forged, expired or unsolved
+	ErrProofReused      = errors.New("proof of work already used")       // challenge was already spent
+	ErrThreadLocked     = errors.New("thread is locked")                 // replies are closed in a locked subtree
+	ErrNotRootComment   = errors.New("only root comments can be pinned") // pinned comment is a reply
+	ErrInvalidMove      = errors.New("comment can not be moved there")   // new parent is the comment itself or one of its replies
 )
@@ -0,0 +1,40 @@
+package filter
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"strings"
+	"unicode"
+)
+
+// BannedWords matches comments containing any of a list of words. Words are
+// compared case-insensitively and only as whole words.
+type BannedWords struct {
+	verdict Verdict
+	words   map[string]struct{}
+}
+
+func NewBannedWords(verdict Verdict, words []string) *BannedWords {
+	set := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		set[strings.ToLower(strings.TrimSpace(word))] = struct{}{}
+	}
+	return &BannedWords{verdict: verdict, words: set}
+}
+
+func (f *BannedWords) Check(_ context.Context, comment models.Comment) (Result, error) {
+
+	words := strings.FieldsFunc(strings.ToLower(comment.Content), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	for _, word := range words {
+		if _, ok := f.words[word]; ok {
+			return Result{Verdict: f.verdict, Reason: errs.ErrBannedWords}, nil
+		}
+	}
+
+	return allowed, nil
+
+}
@@ -0,0 +1,40 @@
+package filter
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// duplicateTrim is the whitespace ignored around a comment when comparing it
+// with earlier ones; storage trims the same characters from stored comments.
+const duplicateTrim = " \t\n\r\f\v"
+
+// Duplicates matches comments whose author posted the same text within window.
+type Duplicates struct {
+	verdict Verdict
+	window  time.Duration
+	finder  DuplicateFinder
+}
+
+func NewDuplicates(verdict Verdict, window time.Duration, finder DuplicateFinder) *Duplicates {
+	return &Duplicates{verdict: verdict, window: window, finder: finder}
+}
+
+func (f *Duplicates) Check(ctx context.Context, comment models.Comment) (Result, error) {
+
+	duplicate, err := f.finder.HasRecentDuplicate(ctx, comment.Author, strings.Trim(comment.Content, duplicateTrim), f.window)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to look up duplicates: %w", err)
+	}
+
+	if duplicate {
+		return Result{Verdict: f.verdict, Reason: errs.ErrDuplicateComment}, nil
+	}
+
+	return allowed, nil
+
+}
@@ -0,0 +1,104 @@
+package filter
+
+import (
+	"Hermes/internal/config"
+	"Hermes/internal/models"
+	"context"
+	"fmt"
+	"time"
+)
+
+// Verdict is a content filter's decision on a new comment.
+type Verdict int
+
+const (
+	Allow  Verdict = iota // publish as usual
+	Flag                  // hold for moderation
+	Reject                // refuse the comment
+)
+
+const (
+	actionAllow  = "allow"
+	actionFlag   = "flag"
+	actionReject = "reject"
+)
+
+// Result is a filter's verdict together with the errs value explaining it.
+// Reason is nil when the comment is allowed.
+type Result struct {
+	Verdict Verdict
+	Reason  error
+}
+
+// allowed is the Result of a filter that found nothing.
+var allowed = Result{Verdict: Allow}
+
+// ContentFilter inspects a new comment before it is stored. An error means
+// the check itself failed.
+type ContentFilter interface {
+	Check(ctx context.Context, comment models.Comment) (Result, error)
+}
+
+// DuplicateFinder reports whether author already posted content within window.
+type DuplicateFinder interface {
+	HasRecentDuplicate(ctx context.Context, author, content string, window time.Duration) (bool, error)
+}
+
+// New builds the built-in filters enabled in config, in the order they run:
+// banned words, links, repeated characters and duplicates. A filter is
+// disabled when its action is "allow" or empty, or its limit is zero.
+func New(config config.Filters, finder DuplicateFinder) ([]ContentFilter, error) {
+
+	var filters []ContentFilter
+
+	add := func(name, action string, enabled bool, build func(Verdict) ContentFilter) error {
+		verdict, err := parseAction(action)
+		if err != nil {
+			return fmt.Errorf("filter: %s: %w", name, err)
+		}
+		if verdict != Allow && enabled {
+			filters = append(filters, build(verdict))
+		}
+		return nil
+	}
+
+	if err := add("banned_words", config.BannedWords.Action, len(config.BannedWords.Words) > 0, func(v Verdict) ContentFilter {
+		return NewBannedWords(v, config.BannedWords.Words)
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := add("links", config.Links.Action, config.Links.Max > 0, func(v Verdict) ContentFilter {
+		return NewLinks(v, config.Links.Max)
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := add("repeated_chars", config.RepeatedChars.Action, config.RepeatedChars.Max > 0, func(v Verdict) ContentFilter {
+		return NewRepeatedChars(v, config.RepeatedChars.Max)
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := add("duplicates", config.Duplicates.Action, config.Duplicates.Window > 0, func(v Verdict) ContentFilter {
+		return NewDuplicates(v, config.Duplicates.Window, finder)
+	}); err != nil {
+		return nil, err
+	}
+
+	return filters, nil
+
+}
+
+func parseAction(action string) (Verdict, error) {
+	switch action {
+	case "", actionAllow:
+		return Allow, nil
+	case actionFlag:
+		return Flag, nil
+	case actionReject:
+		return Reject, nil
+	default:
+		return Allow, fmt.Errorf("unknown action %q", action)
+	}
+}
@@ -0,0 +1,169 @@
+package filter
+
+import (
+	"Hermes/internal/config"
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type stubFinder struct {
+	duplicate bool
+	err       error
+	content   *string
+}
+
+func (f stubFinder) HasRecentDuplicate(_ context.Context, _, content string, _ time.Duration) (bool, error) {
+	if f.content != nil {
+		*f.content = content
+	}
+	return f.duplicate, f.err
+}
+
+func check(t *testing.T, f ContentFilter, content string) Result {
+	t.Helper()
+	result, err := f.Check(context.Background(), models.Comment{Content: content, Author: "user"})
+	require.NoError(t, err)
+	return result
+}
+
+func TestBannedWords(t *testing.T) {
+
+	f := NewBannedWords(Reject, []string{" Casino ", "spam"})
+
+	t.Run("whole word in any case", func(t *testing.T) {
+		require.Equal(t, Result{Verdict: Reject, Reason: errs.ErrBannedWords}, check(t, f, "Best CASINO in town"))
+	})
+
+	t.Run("word between punctuation", func(t *testing.T) {
+		require.Equal(t, Reject, check(t, f, "no (spam), please").Verdict)
+	})
+
+	t.Run("word inside another word", func(t *testing.T) {
+		require.Equal(t, allowed, check(t, f, "casinos and spammers"))
+	})
+
+}
+
+func TestLinks(t *testing.T) {
+
+	f := NewLinks(Flag, 1)
+
+	t.Run("one link at the limit", func(t *testing.T) {
+		require.Equal(t, allowed, check(t, f, "see https://a.example"))
+	})
+
+	t.Run("scheme and www links", func(t *testing.T) {
+		require.Equal(t, Result{Verdict: Flag, Reason: errs.ErrTooManyLinks}, check(t, f, "see HTTP://a.example and www.b.example"))
+	})
+
+	t.Run("two www links", func(t *testing.T) {
+		require.Equal(t, Flag, check(t, f, "www.a.example www.b.example").Verdict)
+	})
+
+	t.Run("text that only looks like a link", func(t *testing.T) {
+		require.Equal(t, allowed, check(t, f, "http:// is a scheme and awww.example is not a link, nor is https://"))
+	})
+
+}
+
+func TestRepeatedChars(t *testing.T) {
+
+	f := NewRepeatedChars(Reject, 3)
+
+	t.Run("run exactly at max", func(t *testing.T) {
+		require.Equal(t, allowed, check(t, f, "wow!!!"))
+	})
+
+	t.Run("run past max", func(t *testing.T) {
+		require.Equal(t, Result{Verdict: Reject, Reason: errs.ErrRepeatedChars}, check(t, f, "wow!!!!"))
+	})
+
+	t.Run("multibyte run", func(t *testing.T) {
+		require.Equal(t, Reject, check(t, f, "ааааа").Verdict)
+	})
+
+	t.Run("whitespace is ignored", func(t *testing.T) {
+		require.Equal(t, allowed, check(t, f, "line\n\n\n\n\n    indented\t\t\t\tend"))
+	})
+
+	t.Run("whitespace breaks a run", func(t *testing.T) {
+		require.Equal(t, allowed, check(t, f, "!!! !!!"))
+	})
+
+}
+
+func TestDuplicates(t *testing.T) {
+
+	t.Run("duplicate", func(t *testing.T) {
+		f := NewDuplicates(Reject, time.Minute, stubFinder{duplicate: true})
+		require.Equal(t, Result{Verdict: Reject, Reason: errs.ErrDuplicateComment}, check(t, f, "hello"))
+	})
+
+	t.Run("surrounding whitespace is trimmed", func(t *testing.T) {
+		var content string
+		f := NewDuplicates(Reject, time.Minute, stubFinder{content: &content})
+		check(t, f, "\t hello\r\n\n")
+		require.Equal(t, "hello", content)
+	})
+
+	t.Run("finder fails", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		f := NewDuplicates(Reject, time.Minute, stubFinder{err: dbErr})
+		_, err := f.Check(context.Background(), models.Comment{Content: "hello"})
+		require.ErrorIs(t, err, dbErr)
+	})
+
+}
+
+func TestNew(t *testing.T) {
+
+	t.Run("all filters in order", func(t *testing.T) {
+		filters, err := New(config.Filters{
+			BannedWords:   config.BannedWordsFilter{Action: "reject", Words: []string{"casino"}},
+			Links:         config.LimitFilter{Action: "flag", Max: 1},
+			RepeatedChars: config.LimitFilter{Action: "reject", Max: 3},
+			Duplicates:    config.DuplicatesFilter{Action: "flag", Window: time.Minute},
+		}, stubFinder{})
+		require.NoError(t, err)
+		require.Len(t, filters, 4)
+		require.IsType(t, &BannedWords{}, filters[0])
+		require.IsType(t, &Links{}, filters[1])
+		require.IsType(t, &RepeatedChars{}, filters[2])
+		require.IsType(t, &Duplicates{}, filters[3])
+	})
+
+	t.Run("disabled filters", func(t *testing.T) {
+		filters, err := New(config.Filters{
+			BannedWords:   config.BannedWordsFilter{Action: "reject"},
+			Links:         config.LimitFilter{Action: "allow", Max: 1},
+			RepeatedChars: config.LimitFilter{Max: 3},
+			Duplicates:    config.DuplicatesFilter{Action: "flag"},
+		}, stubFinder{})
+		require.NoError(t, err)
+		require.Empty(t, filters)
+	})
+
+	for name, filters := range map[string]config.Filters{
+		"banned_words":   {BannedWords: config.BannedWordsFilter{Action: "block", Words: []string{"casino"}}},
+		"links":          {Links: config.LimitFilter{Action: "drop", Max: 1}},
+		"repeated_chars": {RepeatedChars: config.LimitFilter{Action: "Reject", Max: 3}},
+		"duplicates":     {Duplicates: config.DuplicatesFilter{Action: "hold", Window: time.Minute}},
+	} {
+		t.Run("unknown "+name+" action", func(t *testing.T) {
+			_, err := New(filters, stubFinder{})
+			require.ErrorContains(t, err, "filter: "+name+": unknown action")
+		})
+	}
+
+	t.Run("unknown action on a disabled filter", func(t *testing.T) {
+		_, err := New(config.Filters{Links: config.LimitFilter{Action: "drop"}}, stubFinder{})
+		require.Error(t, err)
+	})
+
+}
@@ -0,0 +1,30 @@
+package filter
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"regexp"
+)
+
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+// Links matches comments with more than max links.
+type Links struct {
+	verdict Verdict
+	max     int
+}
+
+func NewLinks(verdict Verdict, max int) *Links {
+	return &Links{verdict: verdict, max: max}
+}
+
+func (f *Links) Check(_ context.Context, comment models.Comment) (Result, error) {
+
+	if len(linkPattern.FindAllStringIndex(comment.Content, f.max+1)) > f.max {
+		return Result{Verdict: f.verdict, Reason: errs.ErrTooManyLinks}, nil
+	}
+
+	return allowed, nil
+
+}
@@ -0,0 +1,44 @@
+package filter
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"unicode"
+)
+
+// RepeatedChars matches comments that repeat one character more than max
+// times in a row, such as "!!!!!!!!!!!!" or "aaaaaaaaaaaa". Whitespace is
+// ignored, so indentation and blank lines never count as a run.
+type RepeatedChars struct {
+	verdict Verdict
+	max     int
+}
+
+func NewRepeatedChars(verdict Verdict, max int) *RepeatedChars {
+	return &RepeatedChars{verdict: verdict, max: max}
+}
+
+func (f *RepeatedChars) Check(_ context.Context, comment models.Comment) (Result, error) {
+
+	var last rune
+	run := 0
+
+	for _, r := range comment.Content {
+		if unicode.IsSpace(r) {
+			last, run = 0, 0
+			continue
+		}
+		if r == last {
+			run++
+		} else {
+			last, run = r, 1
+		}
+		if run > f.max {
+			return Result{Verdict: f.verdict, Reason: errs.ErrRepeatedChars}, nil
+		}
+	}
+
+	return allowed, nil
+
+}
@@ -1,8 +1,12 @@
 package handler
 
 import (
+	"Hermes/internal/auth"
+	"Hermes/internal/config"
 	v1 "Hermes/internal/handler/v1"
+	"Hermes/internal/ratelimit"
 	"Hermes/internal/service"
+	"fmt"
 	"net/http"
 	"text/template"
 
@@ -11,23 +15,65 @@ import (
 
 const templatePath = "web/templates/index.html"
 
-func NewHandler(service service.Service) http.Handler {
+// NewHandler builds the HTTP router. authenticator may be nil when
+// authentication is disabled in config. Client IPs are read from forwarding
+// headers only for requests coming from config.Server.TrustedProxies.
+func NewHandler(service service.Service, authenticator auth.Authenticator, limiter ratelimit.Limiter, config config.Config) (http.Handler, error) {
 
 	handler := ginext.New("")
 
+	if err := handler.SetTrustedProxies(config.Server.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("failed to set trusted proxies: %w", err)
+	}
+
 	handler.Use(ginext.Recovery())
 	handler.Static("/static", "./web/static")
 
 	apiV1 := handler.Group("/api/v1")
 	handlerV1 := v1.NewHandler(service)
 
-	apiV1.POST("/comments", handlerV1.CreateComment)
+	// Without authentication every caller is an anonymous commenter, so
+	// moderation, locking and key management are never reachable.
+	require := v1.RequireUnauthenticated
+	if authenticator != nil {
+		apiV1.Use(v1.ThrottleCredentials(limiter, config.Server.CredentialRateLimit))
+		apiV1.Use(handlerV1.Authenticate(authenticator, config.Auth.AllowAnonymousReads))
+		require = v1.Require
+	}
+
+	apiV1.Use(v1.RateLimit(limiter, config.Server.RateLimits))
+
+	apiV1.GET("/challenge", handlerV1.IssueChallenge)
+	apiV1.POST("/comments", require(auth.PermCreate), handlerV1.CreateComment)
 	apiV1.GET("/comments", handlerV1.GetComments)
-	apiV1.DELETE("/comments/:id", handlerV1.DeleteComment)
+	apiV1.GET("/comments/search", handlerV1.SearchComments)
+	apiV1.GET("/comments/:id", handlerV1.GetComment)
+	apiV1.PATCH("/comments/:id", require(auth.PermCreate), handlerV1.UpdateComment)
+	apiV1.GET("/comments/:id/revisions", handlerV1.GetRevisions)
+	apiV1.GET("/comments/:id/children", handlerV1.GetChildren)
+	apiV1.POST("/comments/:id/vote", require(auth.PermCreate), handlerV1.VoteComment)
+	apiV1.POST("/comments/:id/reactions/:emoji", require(auth.PermCreate), handlerV1.AddReaction)
+	apiV1.DELETE("/comments/:id/reactions/:emoji", require(auth.PermCreate), handlerV1.RemoveReaction)
+	apiV1.POST("/comments/:id/report", require(auth.PermCreate), handlerV1.ReportComment)
+	apiV1.POST("/comments/:id/lock", require(auth.PermLockThread), handlerV1.LockComment)
+	apiV1.DELETE("/comments/:id/lock", require(auth.PermLockThread), handlerV1.UnlockComment)
+	apiV1.POST("/comments/:id/pin", require(auth.PermModerate), handlerV1.PinComment)
+	apiV1.DELETE("/comments/:id/pin", require(auth.PermModerate), handlerV1.UnpinComment)
+	apiV1.POST("/comments/:id/move", require(auth.PermModerate), handlerV1.MoveComment)
+	apiV1.DELETE("/comments/:id", require(auth.PermCreate), handlerV1.DeleteComment)
+
+	apiV1.GET("/moderation/queue", require(auth.PermModerate), handlerV1.GetModerationQueue)
+	apiV1.POST("/moderation/queue/:id/approve", require(auth.PermModerate), handlerV1.ApproveComment)
+	apiV1.POST("/moderation/queue/:id/reject", require(auth.PermModerate), handlerV1.RejectComment)
+	apiV1.GET("/moderation/reports", require(auth.PermModerate), handlerV1.GetReportedComments)
+
+	apiV1.POST("/api-keys", require(auth.PermManageKeys), handlerV1.CreateAPIKey)
+	apiV1.GET("/api-keys", require(auth.PermManageKeys), handlerV1.ListAPIKeys)
+	apiV1.DELETE("/api-keys/:id", require(auth.PermManageKeys), handlerV1.RevokeAPIKey)
 
 	handler.GET("/", homePage(template.Must(template.ParseFiles(templatePath))))
 
-	return handler
+	return handler, nil
 
 }
 
@@ -0,0 +1,137 @@
+package v1
+
+import (
+	"Hermes/internal/auth"
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"net/http"
+	"strings"
+
+	"github.com/wb-go/wbf/ginext"
+)
+
+const (
+	bearerPrefix      = "Bearer "
+	apiKeyHeader      = "X-API-Key"
+	authorTokenHeader = "X-Author-Token"
+)
+
+// Authenticate requires credentials on every request and stores the caller's
+// identity, with its roles resolved, in the request context. Integrations
+// authenticate with an X-API-Key header, users with a bearer token. When
+// allowAnonymousReads is set, GET and HEAD requests without credentials pass
+// through anonymously; credentials that are present are always verified.
+func (h *Handler) Authenticate(authenticator auth.Authenticator, allowAnonymousReads bool) ginext.HandlerFunc {
+	return func(c *ginext.Context) {
+
+		apiKey := c.GetHeader(apiKeyHeader)
+		header := c.GetHeader("Authorization")
+
+		if apiKey == "" && header == "" && allowAnonymousReads && isRead(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		var identity models.Identity
+		var err error
+
+		if apiKey != "" {
+			identity, err = h.service.AuthenticateAPIKey(c.Request.Context(), apiKey)
+		} else {
+			identity, err = h.authenticateBearer(c, authenticator, header)
+		}
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+
+		c.Request = c.Request.WithContext(auth.WithIdentity(c.Request.Context(), identity))
+		c.Next()
+
+	}
+}
+
+func (h *Handler) authenticateBearer(c *ginext.Context, authenticator auth.Authenticator, header string) (models.Identity, error) {
+
+	token, ok := strings.CutPrefix(header, bearerPrefix)
+	if !ok || token == "" {
+		c.Header("WWW-Authenticate", "Bearer")
+		return models.Identity{}, errs.ErrUnauthorized
+	}
+
+	identity, err := authenticator.Authenticate(token)
+	if err != nil {
+		c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+		return models.Identity{}, err
+	}
+
+	return h.service.ResolveRoles(c.Request.Context(), identity)
+
+}
+
+// Require lets the request through only if the caller holds permission.
+// Anonymous callers have the reader role.
+func Require(permission auth.Permission) ginext.HandlerFunc {
+	return requireAs(auth.RoleReader, permission)
+}
+
+// RequireUnauthenticated is Require for deployments without authentication,
+// where every caller is an anonymous commenter: they may post and manage
+// their own comments, but privileged routes stay closed.
+func RequireUnauthenticated(permission auth.Permission) ginext.HandlerFunc {
+	return requireAs(auth.RoleCommenter, permission)
+}
+
+func requireAs(anonymousRole string, permission auth.Permission) ginext.HandlerFunc {
+	return func(c *ginext.Context) {
+
+		identity, ok := auth.IdentityFromContext(c.Request.Context())
+		if !ok {
+			identity = models.Identity{Roles: []string{anonymousRole}}
+		}
+
+		if !auth.Can(identity, permission) {
+			respondError(c, errs.ErrForbidden)
+			return
+		}
+
+		c.Next()
+
+	}
+}
+
+func isRead(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+// caller returns the authenticated caller, or an identity named fallback when
+// the request is anonymous because authentication is disabled.
+func caller(c *ginext.Context, fallback string) models.Identity {
+	if identity, ok := auth.IdentityFromContext(c.Request.Context()); ok {
+		return identity
+	}
+	return models.Identity{Subject: fallback}
+}
+
+// modifier returns the authenticated caller, or an anonymous identity
+// carrying the X-Author-Token header when authentication is disabled.
+// Edits and deletes are authorized against it.
+func modifier(c *ginext.Context) models.Identity {
+	if identity, ok := auth.IdentityFromContext(c.Request.Context()); ok {
+		return identity
+	}
+	return models.Identity{AuthorToken: c.GetHeader(authorTokenHeader)}
+}
+
+// callerOr returns the authenticated caller's subject, or fallback when the
+// request is anonymous because authentication is disabled.
+func callerOr(c *ginext.Context, fallback string) string {
+	return caller(c, fallback).Subject
+}
+
+// callerOrIP returns the authenticated caller's subject, or the client's IP
+// address when authentication is disabled, so that one anonymous client
+// counts once however many names it sends.
+func callerOrIP(c *ginext.Context) string {
+	return callerOr(c, "ip:"+c.ClientIP())
+}
@@ -0,0 +1,26 @@
+package v1
+
+import (
+	"Hermes/internal/errs"
+
+	"github.com/wb-go/wbf/ginext"
+)
+
+func (h *Handler) CreateAPIKey(c *ginext.Context) {
+
+	var request CreateAPIKeyV1
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, errs.ErrInvalidJSON)
+		return
+	}
+
+	key, secret, err := h.service.CreateAPIKey(c.Request.Context(), request.Name, request.Scopes, caller(c, ""))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondOK(c, APIKeyCreatedV1{APIKey: key, Key: secret})
+
+}
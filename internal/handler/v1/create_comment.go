@@ -3,6 +3,7 @@ package v1
 import (
 	"Hermes/internal/errs"
 	"Hermes/internal/models"
+	"strings"
 
 	"github.com/wb-go/wbf/ginext"
 )
@@ -17,17 +18,24 @@ func (h *Handler) CreateComment(c *ginext.Context) {
 	}
 
 	comment := models.Comment{
-		ParentID: request.ParentID,
-		Content:  request.Content,
-		Author:   request.Author,
+		ParentID:  request.ParentID,
+		ThreadKey: strings.TrimSpace(request.ThreadKey),
+		Content:   request.Content,
+		Author:    callerOr(c, request.Author),
 	}
 
-	id, err := h.service.CreateComment(c.Request.Context(), comment)
+	proof := models.Proof{Challenge: request.Challenge, Nonce: request.Nonce}
+
+	id, token, err := h.service.CreateComment(c.Request.Context(), comment, proof)
 	if err != nil {
 		respondError(c, err)
 		return
 	}
 
+	if token != "" {
+		c.Header(authorTokenHeader, token)
+	}
+
 	respondOK(c, id)
 
 }
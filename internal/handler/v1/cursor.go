@@ -0,0 +1,78 @@
+package v1
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// encodeCursor turns the keyset position of a root comment into an opaque
+// token. Pinned comments also carry the time they were pinned.
+func encodeCursor(comment models.Comment) string {
+	raw := comment.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + strconv.FormatInt(comment.ID, 10)
+	if comment.PinnedAt != nil {
+		raw += "|" + comment.PinnedAt.UTC().Format(time.RFC3339Nano)
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(token string) (*models.Cursor, error) {
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errs.ErrInvalidCursor
+	}
+
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, errs.ErrInvalidCursor
+	}
+
+	id, pinnedAt, pinned := strings.Cut(id, "|")
+
+	cursor := &models.Cursor{}
+
+	if cursor.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return nil, errs.ErrInvalidCursor
+	}
+
+	if cursor.ID, err = strconv.ParseInt(id, 10, 64); err != nil {
+		return nil, errs.ErrInvalidCursor
+	}
+
+	if pinned {
+		at, err := time.Parse(time.RFC3339Nano, pinnedAt)
+		if err != nil {
+			return nil, errs.ErrInvalidCursor
+		}
+		cursor.PinnedAt = &at
+	}
+
+	return cursor, nil
+
+}
+
+// nextCursor returns the cursor for the page after comments, or an empty
+// string when the page is not full and there is nothing left to fetch.
+func nextCursor(comments []models.Comment, params models.QueryParams) string {
+	if params.ParentID != nil || !isTimeSort(params.Sort) || len(comments) == 0 || len(comments) < params.Limit {
+		return ""
+	}
+	return encodeCursor(comments[len(comments)-1])
+}
+
+// hasMore reports whether another page follows. In offset mode it is exact;
+// in cursor mode the position within total is unknown, so a full page is
+// taken to mean there may be more.
+func hasMore(comments []models.Comment, total int, params models.QueryParams) bool {
+	if params.ParentID != nil {
+		return false
+	}
+	if params.Cursor != nil {
+		return len(comments) == params.Limit
+	}
+	return params.Offset+len(comments) < total
+}
@@ -14,7 +14,7 @@ func (h *Handler) DeleteComment(c *ginext.Context) {
 		return
 	}
 
-	err = h.service.DeleteComment(c.Request.Context(), id)
+	err = h.service.DeleteComment(c.Request.Context(), id, modifier(c))
 	if err != nil {
 		respondError(c, err)
 		return
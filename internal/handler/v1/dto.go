@@ -1,7 +1,79 @@
 package v1
 
+import (
+	"Hermes/internal/models"
+	"encoding/json"
+)
+
 type CreateCommentV1 struct {
-	ParentID *int64 `json:"parent_id,omitempty"`
-	Content  string `json:"content"`
-	Author   string `json:"author"`
+	ParentID  *int64 `json:"parent_id,omitempty"`
+	ThreadKey string `json:"thread_key"`
+	Content   string `json:"content"`
+	Author    string `json:"author"`
+	Challenge string `json:"challenge,omitempty"`
+	Nonce     string `json:"nonce,omitempty"`
+}
+
+type UpdateCommentV1 struct {
+	Content string `json:"content"`
+}
+
+type VoteV1 struct {
+	Value int `json:"value"`
+}
+
+type ReactionV1 struct {
+	Reactor string `json:"reactor"`
+}
+
+type ReportV1 struct {
+	Reason string `json:"reason"`
+	Note   string `json:"note"`
+}
+
+// MoveCommentV1 requires parent_id to be present so that a misspelled or
+// forgotten field never moves a reply to the top level; only an explicit
+// null does.
+type MoveCommentV1 struct {
+	ParentID json.RawMessage `json:"parent_id"`
+}
+
+type CreateAPIKeyV1 struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+}
+
+type APIKeyCreatedV1 struct {
+	models.APIKey
+	Key string `json:"key"`
+}
+
+type CommentV1 struct {
+	models.Comment
+	Ancestors []models.Comment `json:"ancestors,omitempty"`
+}
+
+type ListResponseV1 struct {
+	Result     []models.Comment `json:"result"`
+	Total      int              `json:"total"`
+	Page       int              `json:"page"`
+	Limit      int              `json:"limit"`
+	HasMore    bool             `json:"has_more"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+type ReportListResponseV1 struct {
+	Result  []models.ReportedComment `json:"result"`
+	Total   int                      `json:"total"`
+	Page    int                      `json:"page"`
+	Limit   int                      `json:"limit"`
+	HasMore bool                     `json:"has_more"`
+}
+
+type SearchResponseV1 struct {
+	Result  []models.SearchResult `json:"result"`
+	Total   int                   `json:"total"`
+	Page    int                   `json:"page"`
+	Limit   int                   `json:"limit"`
+	HasMore bool                  `json:"has_more"`
 }
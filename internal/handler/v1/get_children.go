@@ -0,0 +1,57 @@
+package v1
+
+import (
+	"Hermes/internal/models"
+
+	"github.com/wb-go/wbf/ginext"
+)
+
+func (h *Handler) GetChildren(c *ginext.Context) {
+
+	id, err := parseParam(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	page, limit, err := parsePagination(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	limits, err := parseTreeLimits(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	sort, err := parseSort(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	queryParams := models.QueryParams{
+		Page:       page,
+		Limit:      limit,
+		Sort:       sort,
+		Offset:     (page - 1) * limit,
+		TreeLimits: limits,
+	}
+
+	children, total, err := h.service.GetChildren(c.Request.Context(), id, queryParams)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondList(c, ListResponseV1{
+		Result:  children,
+		Total:   total,
+		Page:    page,
+		Limit:   limit,
+		HasMore: queryParams.Offset+len(children) < total,
+	})
+
+}
@@ -0,0 +1,48 @@
+package v1
+
+import (
+	"Hermes/internal/errs"
+	"strings"
+
+	"github.com/wb-go/wbf/ginext"
+)
+
+const includeAncestors = "ancestors"
+
+func (h *Handler) GetComment(c *ginext.Context) {
+
+	id, err := parseParam(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	withAncestors := false
+	if val := c.Query("include"); val != "" {
+		for _, include := range strings.Split(val, ",") {
+			if strings.TrimSpace(include) != includeAncestors {
+				respondError(c, errs.ErrInvalidInclude)
+				return
+			}
+			withAncestors = true
+		}
+	}
+
+	comment, err := h.service.GetComment(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := CommentV1{Comment: comment}
+
+	if withAncestors {
+		if response.Ancestors, err = h.service.GetAncestors(c.Request.Context(), id); err != nil {
+			respondError(c, err)
+			return
+		}
+	}
+
+	respondOK(c, response)
+
+}
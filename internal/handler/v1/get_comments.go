@@ -18,6 +18,24 @@ func (h *Handler) GetComments(c *ginext.Context) {
 		return
 	}
 
-	respondOK(c, comments)
+	total, err := h.service.CountComments(c.Request.Context(), queryParams)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := ListResponseV1{
+		Result:  comments,
+		Total:   total,
+		Page:    queryParams.Page,
+		Limit:   queryParams.Limit,
+		HasMore: hasMore(comments, total, queryParams),
+	}
+
+	if response.HasMore {
+		response.NextCursor = nextCursor(comments, queryParams)
+	}
+
+	respondList(c, response)
 
 }
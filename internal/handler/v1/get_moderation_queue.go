@@ -0,0 +1,37 @@
+package v1
+
+import (
+	"Hermes/internal/models"
+
+	"github.com/wb-go/wbf/ginext"
+)
+
+func (h *Handler) GetModerationQueue(c *ginext.Context) {
+
+	page, limit, err := parsePagination(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	queryParams := models.QueryParams{
+		Page:   page,
+		Limit:  limit,
+		Offset: (page - 1) * limit,
+	}
+
+	comments, total, err := h.service.GetPendingComments(c.Request.Context(), queryParams)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondList(c, ListResponseV1{
+		Result:  comments,
+		Total:   total,
+		Page:    page,
+		Limit:   limit,
+		HasMore: queryParams.Offset+len(comments) < total,
+	})
+
+}
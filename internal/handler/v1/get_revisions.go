@@ -0,0 +1,23 @@
+package v1
+
+import (
+	"github.com/wb-go/wbf/ginext"
+)
+
+func (h *Handler) GetRevisions(c *ginext.Context) {
+
+	id, err := parseParam(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	revisions, err := h.service.GetRevisions(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondOK(c, revisions)
+
+}
@@ -1,30 +1,59 @@
 package v1
 
 import (
+	"Hermes/internal/auth"
+	"Hermes/internal/config"
 	"Hermes/internal/errs"
 	"Hermes/internal/models"
+	"Hermes/internal/ratelimit"
 	mockService "Hermes/internal/service/mocks"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/require"
 	"github.com/wb-go/wbf/ginext"
 	"go.uber.org/mock/gomock"
 )
 
-func setupRouter(handler *Handler) *ginext.Engine {
+func setupRouter(handler *Handler, middleware ...ginext.HandlerFunc) *ginext.Engine {
 
 	r := ginext.New("")
 
-	v1 := r.Group("/api/v1")
+	v1 := r.Group("/api/v1", middleware...)
 	{
+		v1.GET("/challenge", handler.IssueChallenge)
 		v1.POST("/comments", handler.CreateComment)
 		v1.GET("/comments", handler.GetComments)
+		v1.GET("/comments/search", handler.SearchComments)
+		v1.GET("/comments/:id", handler.GetComment)
+		v1.PATCH("/comments/:id", handler.UpdateComment)
+		v1.GET("/comments/:id/revisions", handler.GetRevisions)
+		v1.GET("/comments/:id/children", handler.GetChildren)
+		v1.POST("/comments/:id/vote", handler.VoteComment)
+		v1.POST("/comments/:id/reactions/:emoji", handler.AddReaction)
+		v1.DELETE("/comments/:id/reactions/:emoji", handler.RemoveReaction)
+		v1.POST("/comments/:id/report", handler.ReportComment)
+		v1.POST("/comments/:id/pin", handler.PinComment)
+		v1.DELETE("/comments/:id/pin", handler.UnpinComment)
+		v1.POST("/comments/:id/move", handler.MoveComment)
+		v1.POST("/comments/:id/lock", handler.LockComment)
+		v1.DELETE("/comments/:id/lock", handler.UnlockComment)
 		v1.DELETE("/comments/:id", handler.DeleteComment)
+		v1.GET("/moderation/queue", handler.GetModerationQueue)
+		v1.POST("/moderation/queue/:id/approve", handler.ApproveComment)
+		v1.POST("/moderation/queue/:id/reject", handler.RejectComment)
+		v1.GET("/moderation/reports", handler.GetReportedComments)
+		v1.POST("/api-keys", handler.CreateAPIKey)
+		v1.GET("/api-keys", handler.ListAPIKeys)
+		v1.DELETE("/api-keys/:id", handler.RevokeAPIKey)
 	}
 
 	return r
@@ -53,7 +82,7 @@ func TestHandler_CreateComment(t *testing.T) {
 		body := CreateCommentV1{ParentID: new(int64), Content: "test", Author: "author"}
 		*body.ParentID = 999
 		b, _ := json.Marshal(body)
-		mockService.EXPECT().CreateComment(gomock.Any(), models.Comment{ParentID: body.ParentID, Content: body.Content, Author: body.Author}).Return(int64(0), errs.ErrParentNotFound)
+		mockService.EXPECT().CreateComment(gomock.Any(), models.Comment{ParentID: body.ParentID, Content: body.Content, Author: body.Author}, models.Proof{}).Return(int64(0), "", errs.ErrParentNotFound)
 		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments", bytes.NewBuffer(b))
 		req.Header.Set("Content-Type", "application/json")
 		w := httptest.NewRecorder()
@@ -61,16 +90,64 @@ func TestHandler_CreateComment(t *testing.T) {
 		require.Equal(t, http.StatusNotFound, w.Code)
 	})
 
+	t.Run("content filters", func(t *testing.T) {
+		for reason, status := range map[error]int{
+			errs.ErrBannedWords:      http.StatusUnprocessableEntity,
+			errs.ErrTooManyLinks:     http.StatusUnprocessableEntity,
+			errs.ErrRepeatedChars:    http.StatusUnprocessableEntity,
+			errs.ErrDuplicateComment: http.StatusConflict,
+		} {
+			mockService.EXPECT().CreateComment(gomock.Any(), models.Comment{Content: "spam", Author: "author"}, models.Proof{}).Return(int64(0), "", reason)
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/comments", bytes.NewBufferString(`{"content":"spam","author":"author"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			require.Equal(t, status, w.Code)
+			require.Contains(t, w.Body.String(), reason.Error())
+		}
+	})
+
+	t.Run("proof of work", func(t *testing.T) {
+		challenge := models.Challenge{Required: true, Challenge: "v1.abc.16.1700000000.sig", Difficulty: 16}
+		mockService.EXPECT().IssueChallenge(gomock.Any()).Return(challenge, nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/challenge", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"challenge":"v1.abc.16.1700000000.sig"`)
+		require.Contains(t, w.Body.String(), `"difficulty":16`)
+
+		proof := models.Proof{Challenge: challenge.Challenge, Nonce: "42"}
+		mockService.EXPECT().CreateComment(gomock.Any(), models.Comment{Content: "test", Author: "anon"}, proof).Return(int64(0), "", errs.ErrProofReused)
+		req = httptest.NewRequest(http.MethodPost, "/api/v1/comments", bytes.NewBufferString(`{"content":"test","author":"anon","challenge":"v1.abc.16.1700000000.sig","nonce":"42"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("thread key", func(t *testing.T) {
+		body := CreateCommentV1{ThreadKey: " blog/post-1 ", Content: "test", Author: "author"}
+		b, _ := json.Marshal(body)
+		mockService.EXPECT().CreateComment(gomock.Any(), models.Comment{ThreadKey: "blog/post-1", Content: body.Content, Author: body.Author}, models.Proof{}).Return(int64(7), "", nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments", bytes.NewBuffer(b))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
 	t.Run("success", func(t *testing.T) {
 		body := CreateCommentV1{Content: "test", Author: "author"}
 		b, _ := json.Marshal(body)
-		mockService.EXPECT().CreateComment(gomock.Any(), models.Comment{Content: body.Content, Author: body.Author}).Return(int64(123), nil)
+		mockService.EXPECT().CreateComment(gomock.Any(), models.Comment{Content: body.Content, Author: body.Author}, models.Proof{}).Return(int64(123), "token", nil)
 		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments", bytes.NewBuffer(b))
 		req.Header.Set("Content-Type", "application/json")
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
 		require.Equal(t, http.StatusOK, w.Code)
 		require.Contains(t, w.Body.String(), "123")
+		require.Equal(t, "token", w.Header().Get("X-Author-Token"))
 	})
 
 }
@@ -86,7 +163,7 @@ func TestHandler_DeleteComment(t *testing.T) {
 	router := setupRouter(h)
 
 	t.Run("comment not found", func(t *testing.T) {
-		mockService.EXPECT().DeleteComment(gomock.Any(), int64(999)).Return(errs.ErrCommentNotFound)
+		mockService.EXPECT().DeleteComment(gomock.Any(), int64(999), models.Identity{}).Return(errs.ErrCommentNotFound)
 		req := httptest.NewRequest(http.MethodDelete, "/api/v1/comments/999", nil)
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
@@ -94,7 +171,7 @@ func TestHandler_DeleteComment(t *testing.T) {
 	})
 
 	t.Run("success delete", func(t *testing.T) {
-		mockService.EXPECT().DeleteComment(gomock.Any(), int64(123)).Return(nil)
+		mockService.EXPECT().DeleteComment(gomock.Any(), int64(123), models.Identity{}).Return(nil)
 		req := httptest.NewRequest(http.MethodDelete, "/api/v1/comments/123", nil)
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
@@ -102,6 +179,154 @@ func TestHandler_DeleteComment(t *testing.T) {
 		require.Contains(t, w.Body.String(), "deleted")
 	})
 
+	t.Run("author token", func(t *testing.T) {
+		mockService.EXPECT().DeleteComment(gomock.Any(), int64(124), models.Identity{AuthorToken: "token"}).Return(nil)
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/comments/124", nil)
+		req.Header.Set("X-Author-Token", "token")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		mockService.EXPECT().DeleteComment(gomock.Any(), int64(5), models.Identity{}).Return(errs.ErrForbidden)
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/comments/5", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+}
+
+func TestHandler_UpdateComment(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mockService.NewMockService(ctrl)
+
+	h := &Handler{service: mockService}
+	router := setupRouter(h)
+
+	t.Run("invalid id", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/comments/abc", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/comments/1", bytes.NewBufferString(`{invalid}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("comment not found", func(t *testing.T) {
+		body := UpdateCommentV1{Content: "fixed"}
+		b, _ := json.Marshal(body)
+		mockService.EXPECT().UpdateComment(gomock.Any(), models.Comment{ID: 999, Content: body.Content}, models.Identity{}).Return(models.Comment{}, errs.ErrCommentNotFound)
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/comments/999", bytes.NewBuffer(b))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		body := UpdateCommentV1{Content: "fixed"}
+		b, _ := json.Marshal(body)
+		updated := models.Comment{ID: 123, Content: body.Content, Author: "author"}
+		mockService.EXPECT().UpdateComment(gomock.Any(), models.Comment{ID: 123, Content: body.Content}, models.Identity{AuthorToken: "token"}).Return(updated, nil)
+		req := httptest.NewRequest(http.MethodPatch, "/api/v1/comments/123", bytes.NewBuffer(b))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Author-Token", "token")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"content":"fixed"`)
+	})
+
+}
+
+func TestHandler_GetComment(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mockService.NewMockService(ctrl)
+
+	h := &Handler{service: mockService}
+	router := setupRouter(h)
+
+	t.Run("comment not found", func(t *testing.T) {
+		mockService.EXPECT().GetComment(gomock.Any(), int64(999)).Return(models.Comment{}, errs.ErrCommentNotFound)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments/999", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("invalid include", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments/1?include=everything", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockService.EXPECT().GetComment(gomock.Any(), int64(3)).Return(models.Comment{ID: 3, Content: "reply"}, nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments/3", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"content":"reply"`)
+		require.NotContains(t, w.Body.String(), `"ancestors"`)
+	})
+
+	t.Run("success with ancestors", func(t *testing.T) {
+		parentID := int64(2)
+		mockService.EXPECT().GetComment(gomock.Any(), int64(3)).Return(models.Comment{ID: 3, ParentID: &parentID}, nil)
+		mockService.EXPECT().GetAncestors(gomock.Any(), int64(3)).Return([]models.Comment{{ID: 1}, {ID: 2}}, nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments/3?include=ancestors", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"ancestors":[{"id":1`)
+	})
+
+}
+
+func TestHandler_GetRevisions(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mockService.NewMockService(ctrl)
+
+	h := &Handler{service: mockService}
+	router := setupRouter(h)
+
+	t.Run("comment not found", func(t *testing.T) {
+		mockService.EXPECT().GetRevisions(gomock.Any(), int64(999)).Return(nil, errs.ErrCommentNotFound)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments/999/revisions", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		revisions := []models.Revision{{ID: 1, CommentID: 123, Content: "tpyo", Author: "author"}}
+		mockService.EXPECT().GetRevisions(gomock.Any(), int64(123)).Return(revisions, nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments/123/revisions", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"content":"tpyo"`)
+	})
+
 }
 
 func TestHandler_GetComments(t *testing.T) {
@@ -135,6 +360,7 @@ func TestHandler_GetComments(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		qp := models.QueryParams{Page: 1, Limit: 20, Sort: "created_at_desc", Offset: 0}
 		mockService.EXPECT().GetComments(gomock.Any(), qp).Return(comments, nil)
+		mockService.EXPECT().CountComments(gomock.Any(), qp).Return(2, nil)
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments", nil)
 		w := httptest.NewRecorder()
 		router.ServeHTTP(w, req)
@@ -144,6 +370,890 @@ func TestHandler_GetComments(t *testing.T) {
 		require.Contains(t, body, `"content":"comment1"`)
 		require.Contains(t, body, `"id":2`)
 		require.Contains(t, body, `"content":"comment2"`)
+		require.Contains(t, body, `"total":2`)
+		require.Contains(t, body, `"page":1`)
+		require.Contains(t, body, `"limit":20`)
+		require.Contains(t, body, `"has_more":false`)
+	})
+
+	t.Run("count error", func(t *testing.T) {
+		qp := models.QueryParams{Page: 1, Limit: 20, Sort: "created_at_desc", Offset: 0}
+		mockService.EXPECT().GetComments(gomock.Any(), qp).Return(comments, nil)
+		mockService.EXPECT().CountComments(gomock.Any(), qp).Return(0, errors.New("db down :("))
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+
+	t.Run("has more pages", func(t *testing.T) {
+		qp := models.QueryParams{Page: 1, Limit: 2, Sort: "created_at_desc", Offset: 0}
+		mockService.EXPECT().GetComments(gomock.Any(), qp).Return(comments, nil)
+		mockService.EXPECT().CountComments(gomock.Any(), qp).Return(3, nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments?limit=2", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"total":3`)
+		require.Contains(t, w.Body.String(), `"has_more":true`)
+		require.Contains(t, w.Body.String(), `"next_cursor":"`+encodeCursor(comments[1])+`"`)
+	})
+
+	t.Run("thread key too long", func(t *testing.T) {
+		qp := models.QueryParams{ThreadKey: strings.Repeat("k", 256), Page: 1, Limit: 20, Sort: "created_at_desc"}
+		mockService.EXPECT().GetComments(gomock.Any(), qp).Return(nil, errs.ErrInvalidThreadKey)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments?thread_key="+strings.Repeat("k", 256), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("scoped by thread key", func(t *testing.T) {
+		qp := models.QueryParams{ThreadKey: "blog/post-1", Page: 1, Limit: 20, Sort: "created_at_desc"}
+		mockService.EXPECT().GetComments(gomock.Any(), qp).Return(comments, nil)
+		mockService.EXPECT().CountComments(gomock.Any(), qp).Return(2, nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments?thread_key=blog/post-1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("invalid max depth", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments?max_depth=0", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("tree limits", func(t *testing.T) {
+		qp := models.QueryParams{Page: 1, Limit: 20, Sort: "created_at_desc", TreeLimits: models.TreeLimits{MaxDepth: 2, MaxChildren: 3}}
+		truncated := []models.Comment{{ID: 1, ChildrenCount: 10, HasMoreChildren: true}}
+		mockService.EXPECT().GetComments(gomock.Any(), qp).Return(truncated, nil)
+		mockService.EXPECT().CountComments(gomock.Any(), qp).Return(1, nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments?max_depth=2&max_children=3", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"children_count":10`)
+		require.Contains(t, w.Body.String(), `"has_more_children":true`)
+	})
+
+	t.Run("score sorts", func(t *testing.T) {
+		for _, sort := range []string{"top", "controversial", "hot"} {
+			qp := models.QueryParams{Page: 1, Limit: 20, Sort: sort}
+			mockService.EXPECT().GetComments(gomock.Any(), qp).Return(comments, nil)
+			mockService.EXPECT().CountComments(gomock.Any(), qp).Return(2, nil)
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/comments?sort="+sort, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			require.Equal(t, http.StatusOK, w.Code)
+		}
+	})
+
+	t.Run("cursor with score sort", func(t *testing.T) {
+		token := encodeCursor(models.Comment{ID: 1, CreatedAt: time.Now()})
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments?sort=top&cursor="+token, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments?cursor=!!!", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("cursor pagination", func(t *testing.T) {
+		last := models.Comment{ID: 7, CreatedAt: time.Date(2025, 1, 2, 3, 4, 5, 6000, time.UTC)}
+		token := encodeCursor(last)
+		qp := models.QueryParams{Page: 1, Limit: 1, Sort: "created_at_desc", Cursor: &models.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}}
+		next := models.Comment{ID: 6, CreatedAt: last.CreatedAt.Add(-time.Second)}
+		mockService.EXPECT().GetComments(gomock.Any(), qp).Return([]models.Comment{next}, nil)
+		mockService.EXPECT().CountComments(gomock.Any(), qp).Return(5, nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments?limit=1&cursor="+token, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"next_cursor":"`+encodeCursor(next)+`"`)
+	})
+
+}
+
+func TestHandler_GetChildren(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mockService.NewMockService(ctrl)
+
+	h := &Handler{service: mockService}
+	router := setupRouter(h)
+
+	t.Run("invalid max children", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments/1/children?max_children=-1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		qp := models.QueryParams{Page: 2, Limit: 5, Sort: "created_at_desc", Offset: 5, TreeLimits: models.TreeLimits{MaxDepth: 1}}
+		children := []models.Comment{{ID: 8}, {ID: 9}}
+		mockService.EXPECT().GetChildren(gomock.Any(), int64(1), qp).Return(children, 12, nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments/1/children?page=2&limit=5&max_depth=1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"id":9`)
+		require.Contains(t, w.Body.String(), `"total":12`)
+		require.Contains(t, w.Body.String(), `"has_more":true`)
+	})
+
+	t.Run("unknown parent", func(t *testing.T) {
+		qp := models.QueryParams{Page: 1, Limit: 20, Sort: "created_at_desc"}
+		mockService.EXPECT().GetChildren(gomock.Any(), int64(2), qp).Return(nil, 0, errs.ErrCommentNotFound)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments/2/children", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+}
+
+func TestHandler_VoteComment(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mockService.NewMockService(ctrl)
+
+	h := &Handler{service: mockService}
+	router := setupRouter(h)
+
+	t.Run("invalid json", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/1/vote", bytes.NewBufferString(`{invalid}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid vote", func(t *testing.T) {
+		mockService.EXPECT().VoteComment(gomock.Any(), int64(1), "ip:192.0.2.1", 5).Return(models.Comment{}, errs.ErrInvalidVote)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/1/vote", bytes.NewBufferString(`{"value":5}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockService.EXPECT().VoteComment(gomock.Any(), int64(1), "ip:192.0.2.1", 1).Return(models.Comment{ID: 1, Score: 3, Upvotes: 4, Downvotes: 1}, nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/1/vote", bytes.NewBufferString(`{"value":1}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"score":3`)
+	})
+
+	t.Run("anonymous voter is the client address", func(t *testing.T) {
+		mockService.EXPECT().VoteComment(gomock.Any(), int64(1), "ip:203.0.113.9", -1).Return(models.Comment{ID: 1}, nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/1/vote", bytes.NewBufferString(`{"voter":"mallory","value":-1}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = "203.0.113.9:4567"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+}
+
+func TestHandler_Reactions(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mockService.NewMockService(ctrl)
+
+	h := &Handler{service: mockService}
+	router := setupRouter(h)
+
+	t.Run("invalid json", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/1/reactions/%F0%9F%91%8D", bytes.NewBufferString(`{invalid}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("reaction not allowed", func(t *testing.T) {
+		mockService.EXPECT().AddReaction(gomock.Any(), int64(1), "nope", "alice").Return(nil, errs.ErrInvalidReaction)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/1/reactions/nope", bytes.NewBufferString(`{"reactor":"alice"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("add", func(t *testing.T) {
+		mockService.EXPECT().AddReaction(gomock.Any(), int64(1), "👍", "alice").Return(map[string]int{"👍": 2}, nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/1/reactions/%F0%9F%91%8D", bytes.NewBufferString(`{"reactor":"alice"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"👍":2`)
+	})
+
+	t.Run("remove from missing comment", func(t *testing.T) {
+		mockService.EXPECT().RemoveReaction(gomock.Any(), int64(9), "👍", "alice").Return(nil, errs.ErrCommentNotFound)
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/comments/9/reactions/%F0%9F%91%8D", bytes.NewBufferString(`{"reactor":"alice"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+}
+
+func TestHandler_SearchComments(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mockService.NewMockService(ctrl)
+
+	h := &Handler{service: mockService}
+	router := setupRouter(h)
+
+	t.Run("empty query", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments/search?q=%20", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("invalid page", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments/search?q=go&page=0", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		sp := models.SearchParams{Query: "hermes", Page: 2, Limit: 1, Offset: 1}
+		results := []models.SearchResult{{Comment: models.Comment{ID: 5, Content: "hermes rocks"}, Rank: 0.5, Ancestors: []int64{1, 3}}}
+		mockService.EXPECT().SearchComments(gomock.Any(), sp).Return(results, 3, nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments/search?q=hermes&page=2&limit=1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		body := w.Body.String()
+		require.Contains(t, body, `"id":5`)
+		require.Contains(t, body, `"ancestors":[1,3]`)
+		require.Contains(t, body, `"total":3`)
+		require.Contains(t, body, `"has_more":true`)
+	})
+
+}
+
+func TestCursor(t *testing.T) {
+
+	comment := models.Comment{ID: 42, CreatedAt: time.Date(2025, 6, 7, 8, 9, 10, 123456000, time.UTC)}
+
+	t.Run("round trip", func(t *testing.T) {
+		cursor, err := decodeCursor(encodeCursor(comment))
+		require.NoError(t, err)
+		require.Equal(t, comment.ID, cursor.ID)
+		require.True(t, comment.CreatedAt.Equal(cursor.CreatedAt))
+	})
+
+	t.Run("pinned round trip", func(t *testing.T) {
+		pinnedAt := time.Date(2025, 6, 8, 0, 0, 0, 0, time.UTC)
+		pinned := comment
+		pinned.PinnedAt = &pinnedAt
+		cursor, err := decodeCursor(encodeCursor(pinned))
+		require.NoError(t, err)
+		require.Equal(t, comment.ID, cursor.ID)
+		require.NotNil(t, cursor.PinnedAt)
+		require.True(t, pinnedAt.Equal(*cursor.PinnedAt))
+
+		cursor, err = decodeCursor(encodeCursor(comment))
+		require.NoError(t, err)
+		require.Nil(t, cursor.PinnedAt)
+	})
+
+	t.Run("malformed token", func(t *testing.T) {
+		for _, token := range []string{"%%%", "bm9waXBl", "YWJjfDEy", "MjAyNS0wMS0wMVQwMDowMDowMFp8eA", "MjAyNS0wMS0wMVQwMDowMDowMFp8MXx4"} {
+			_, err := decodeCursor(token)
+			require.ErrorIs(t, err, errs.ErrInvalidCursor, token)
+		}
+	})
+
+	t.Run("no next cursor on a partial page", func(t *testing.T) {
+		params := models.QueryParams{Limit: 2, Sort: defaultSort}
+		require.Empty(t, nextCursor([]models.Comment{comment}, params))
+		require.NotEmpty(t, nextCursor([]models.Comment{comment, comment}, params))
+	})
+
+	t.Run("no next cursor for score sorts", func(t *testing.T) {
+		params := models.QueryParams{Limit: 1, Sort: topSort}
+		require.Empty(t, nextCursor([]models.Comment{comment}, params))
+	})
+
+}
+
+func TestAuthenticate(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mockService.NewMockService(ctrl)
+
+	const secret = "test-secret"
+
+	authenticator, err := auth.NewAuthenticator(config.Auth{Algorithm: "HS256", Secret: secret, Issuer: "hermes"})
+	require.NoError(t, err)
+
+	sign := func(claims jwt.RegisteredClaims, key string) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(key))
+		require.NoError(t, err)
+		return token
+	}
+
+	valid := sign(jwt.RegisteredClaims{Subject: "alice", Issuer: "hermes", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))}, secret)
+
+	mockService.EXPECT().ResolveRoles(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, identity models.Identity) (models.Identity, error) {
+		if len(identity.Roles) == 0 {
+			identity.Roles = []string{auth.RoleCommenter}
+		}
+		return identity, nil
+	}).AnyTimes()
+
+	h := &Handler{service: mockService}
+	router := setupRouter(h, h.Authenticate(authenticator, true))
+	strict := setupRouter(h, h.Authenticate(authenticator, false))
+
+	post := func(router *ginext.Engine, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments", bytes.NewBufferString(`{"content":"hi","author":"mallory"}`))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("write without token", func(t *testing.T) {
+		w := post(router, "")
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+		require.Equal(t, "Bearer", w.Header().Get("WWW-Authenticate"))
+	})
+
+	t.Run("subject becomes author", func(t *testing.T) {
+		mockService.EXPECT().CreateComment(gomock.Any(), models.Comment{Content: "hi", Author: "alice"}, models.Proof{}).Return(int64(1), "", nil)
+		w := post(router, valid)
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("rejected tokens", func(t *testing.T) {
+		for name, token := range map[string]string{
+			"wrong key":    sign(jwt.RegisteredClaims{Subject: "alice", Issuer: "hermes"}, "other"),
+			"expired":      sign(jwt.RegisteredClaims{Subject: "alice", Issuer: "hermes", ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute))}, secret),
+			"wrong issuer": sign(jwt.RegisteredClaims{Subject: "alice", Issuer: "evil"}, secret),
+			"no subject":   sign(jwt.RegisteredClaims{Issuer: "hermes"}, secret),
+			"malformed":    "not.a.jwt",
+		} {
+			w := post(router, token)
+			require.Equal(t, http.StatusUnauthorized, w.Code, name)
+		}
+	})
+
+	t.Run("rejects other algorithms", func(t *testing.T) {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.RegisteredClaims{Subject: "alice", Issuer: "hermes"}).SignedString(jwt.UnsafeAllowNoneSignatureType)
+		require.NoError(t, err)
+		w := post(router, token)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("identity reaches the service", func(t *testing.T) {
+		token := sign(jwt.RegisteredClaims{Subject: "mod", Issuer: "hermes"}, secret)
+		mockService.EXPECT().DeleteComment(gomock.Any(), int64(1), models.Identity{Subject: "mod", Roles: []string{auth.RoleCommenter}}).Return(nil)
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/comments/1", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("roles claim", func(t *testing.T) {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "mod", "iss": "hermes", "roles": []string{"moderator"}}).SignedString([]byte(secret))
+		require.NoError(t, err)
+		mockService.EXPECT().DeleteComment(gomock.Any(), int64(2), models.Identity{Subject: "mod", Roles: []string{"moderator"}}).Return(nil)
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/comments/2", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("api key", func(t *testing.T) {
+		mockService.EXPECT().AuthenticateAPIKey(gomock.Any(), "hk_secret").Return(models.Identity{Subject: "apikey:shop", Scopes: []string{"create"}}, nil)
+		mockService.EXPECT().CreateComment(gomock.Any(), models.Comment{Content: "hi", Author: "apikey:shop"}, models.Proof{}).Return(int64(3), "", nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments", bytes.NewBufferString(`{"content":"hi"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", "hk_secret")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("revoked api key", func(t *testing.T) {
+		mockService.EXPECT().AuthenticateAPIKey(gomock.Any(), "hk_revoked").Return(models.Identity{}, errs.ErrUnauthorized)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments/1", nil)
+		req.Header.Set("X-API-Key", "hk_revoked")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("anonymous read", func(t *testing.T) {
+		mockService.EXPECT().GetComment(gomock.Any(), int64(1)).Return(models.Comment{ID: 1}, nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments/1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("anonymous read disabled", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/comments/1", nil)
+		w := httptest.NewRecorder()
+		strict.ServeHTTP(w, req)
+		require.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+}
+
+func TestHandler_Moderation(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mockService.NewMockService(ctrl)
+
+	h := &Handler{service: mockService}
+	router := setupRouter(h)
+
+	t.Run("queue", func(t *testing.T) {
+		qp := models.QueryParams{Page: 2, Limit: 1, Offset: 1}
+		mockService.EXPECT().GetPendingComments(gomock.Any(), qp).Return([]models.Comment{{ID: 4, Status: models.StatusPending}}, 3, nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/moderation/queue?page=2&limit=1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"status":"pending"`)
+		require.Contains(t, w.Body.String(), `"has_more":true`)
+	})
+
+	t.Run("approve", func(t *testing.T) {
+		mockService.EXPECT().ApproveComment(gomock.Any(), int64(4)).Return(models.Comment{ID: 4, Status: models.StatusApproved}, nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/moderation/queue/4/approve", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"status":"approved"`)
+	})
+
+	t.Run("reject comment not in queue", func(t *testing.T) {
+		mockService.EXPECT().RejectComment(gomock.Any(), int64(5)).Return(models.Comment{}, errs.ErrCommentNotFound)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/moderation/queue/5/reject", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("pin", func(t *testing.T) {
+		pinnedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		mockService.EXPECT().PinComment(gomock.Any(), int64(6)).Return(models.Comment{ID: 6, Pinned: true, PinnedAt: &pinnedAt}, nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/6/pin", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"pinned":true`)
+	})
+
+	t.Run("pin a reply", func(t *testing.T) {
+		mockService.EXPECT().PinComment(gomock.Any(), int64(7)).Return(models.Comment{}, errs.ErrNotRootComment)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/7/pin", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("unpin", func(t *testing.T) {
+		mockService.EXPECT().UnpinComment(gomock.Any(), int64(6)).Return(models.Comment{ID: 6}, nil)
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/comments/6/pin", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"pinned":false`)
+	})
+
+	t.Run("lock", func(t *testing.T) {
+		lockedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		mockService.EXPECT().LockComment(gomock.Any(), int64(6)).Return(models.Comment{ID: 6, LockedAt: &lockedAt}, nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/6/lock", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"locked_at":"2024-01-01T00:00:00Z"`)
+	})
+
+	t.Run("unlock", func(t *testing.T) {
+		mockService.EXPECT().UnlockComment(gomock.Any(), int64(6)).Return(models.Comment{ID: 6}, nil)
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/comments/6/lock", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.NotContains(t, w.Body.String(), "locked_at")
+	})
+
+	t.Run("reply to locked thread", func(t *testing.T) {
+		parentID := int64(6)
+		mockService.EXPECT().CreateComment(gomock.Any(), models.Comment{ParentID: &parentID, Content: "late", Author: "bob"}, models.Proof{}).Return(int64(0), "", errs.ErrThreadLocked)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments", bytes.NewBufferString(`{"parent_id":6,"content":"late","author":"bob"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusLocked, w.Code)
+	})
+
+	t.Run("move", func(t *testing.T) {
+		parentID := int64(2)
+		mockService.EXPECT().MoveComment(gomock.Any(), int64(7), &parentID).Return(models.Comment{ID: 7, ParentID: &parentID}, nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/7/move", bytes.NewBufferString(`{"parent_id":2}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"parent_id":2`)
+	})
+
+	t.Run("move to root", func(t *testing.T) {
+		mockService.EXPECT().MoveComment(gomock.Any(), int64(7), (*int64)(nil)).Return(models.Comment{ID: 7}, nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/7/move", bytes.NewBufferString(`{"parent_id":null}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("move under own reply", func(t *testing.T) {
+		parentID := int64(8)
+		mockService.EXPECT().MoveComment(gomock.Any(), int64(7), &parentID).Return(models.Comment{}, errs.ErrInvalidMove)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/7/move", bytes.NewBufferString(`{"parent_id":8}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("move with invalid json", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/7/move", bytes.NewBufferString(`{"parent_id":`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	for name, body := range map[string]string{
+		"missing parent_id":    `{}`,
+		"misspelled parent_id": `{"parentId":2}`,
+		"non-numeric parent":   `{"parent_id":"x"}`,
+		"fractional parent":    `{"parent_id":2.5}`,
+	} {
+		t.Run("move with "+name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/7/move", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			require.Equal(t, http.StatusBadRequest, w.Code)
+			require.Contains(t, w.Body.String(), errs.ErrInvalidParentID.Error())
+		})
+	}
+
+}
+
+func TestHandler_Reports(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mockService.NewMockService(ctrl)
+
+	h := &Handler{service: mockService}
+	router := setupRouter(h)
+
+	t.Run("invalid reason", func(t *testing.T) {
+		report := models.Report{CommentID: 1, Reporter: "ip:192.0.2.1", Reason: "boring"}
+		mockService.EXPECT().ReportComment(gomock.Any(), report).Return(models.Comment{}, errs.ErrInvalidReason)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/1/report", bytes.NewBufferString(`{"reason":"boring"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("report", func(t *testing.T) {
+		report := models.Report{CommentID: 1, Reporter: "ip:192.0.2.1", Reason: models.ReasonSpam, Note: "buy now"}
+		mockService.EXPECT().ReportComment(gomock.Any(), report).Return(models.Comment{ID: 1, Status: models.StatusHidden}, nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/1/report", bytes.NewBufferString(`{"reason":"spam","note":"buy now"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"status":"hidden"`)
+	})
+
+	t.Run("anonymous reporter names are ignored", func(t *testing.T) {
+		report := models.Report{CommentID: 2, Reporter: "ip:192.0.2.1", Reason: models.ReasonSpam}
+		mockService.EXPECT().ReportComment(gomock.Any(), report).Return(models.Comment{ID: 2}, nil).Times(2)
+		for _, name := range []string{"alice", "bob"} {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/comments/2/report", bytes.NewBufferString(`{"reporter":"`+name+`","reason":"spam"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			require.Equal(t, http.StatusOK, w.Code)
+		}
+	})
+
+	t.Run("listing", func(t *testing.T) {
+		qp := models.QueryParams{Page: 1, Limit: 20}
+		reported := []models.ReportedComment{{Comment: models.Comment{ID: 1}, Reports: 3, Reasons: map[string]int{"spam": 3}}}
+		mockService.EXPECT().GetReportedComments(gomock.Any(), qp).Return(reported, 1, nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/moderation/reports", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"reports":3`)
+		require.Contains(t, w.Body.String(), `"reasons":{"spam":3}`)
+		require.Contains(t, w.Body.String(), `"has_more":false`)
+	})
+
+}
+
+func TestHandler_APIKeys(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mockService.NewMockService(ctrl)
+
+	h := &Handler{service: mockService}
+	router := setupRouter(h)
+
+	t.Run("create invalid scope", func(t *testing.T) {
+		mockService.EXPECT().CreateAPIKey(gomock.Any(), "shop", []string{"root"}, models.Identity{}).Return(models.APIKey{}, "", errs.ErrInvalidScope)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/api-keys", bytes.NewBufferString(`{"name":"shop","scopes":["root"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("create", func(t *testing.T) {
+		key := models.APIKey{ID: 1, Name: "shop", Prefix: "hk_12345678", Scopes: []string{"create"}}
+		mockService.EXPECT().CreateAPIKey(gomock.Any(), "shop", []string{"create"}, models.Identity{}).Return(key, "hk_12345678abcdef", nil)
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/api-keys", bytes.NewBufferString(`{"name":"shop","scopes":["create"]}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"key":"hk_12345678abcdef"`)
+		require.Contains(t, w.Body.String(), `"prefix":"hk_12345678"`)
+	})
+
+	t.Run("list", func(t *testing.T) {
+		mockService.EXPECT().ListAPIKeys(gomock.Any()).Return([]models.APIKey{{ID: 1, Name: "shop"}}, nil)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/api-keys", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		require.Contains(t, w.Body.String(), `"name":"shop"`)
+		require.NotContains(t, w.Body.String(), `"key"`)
+	})
+
+	t.Run("revoke unknown", func(t *testing.T) {
+		mockService.EXPECT().RevokeAPIKey(gomock.Any(), int64(9)).Return(errs.ErrAPIKeyNotFound)
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/api-keys/9", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("revoke", func(t *testing.T) {
+		mockService.EXPECT().RevokeAPIKey(gomock.Any(), int64(1)).Return(nil)
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/api-keys/1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	})
+
+}
+
+func TestRequire(t *testing.T) {
+
+	router := ginext.New("")
+	router.Use(func(c *ginext.Context) {
+		if role := c.Query("role"); role != "" {
+			identity := models.Identity{Subject: "someone", Roles: []string{role}}
+			c.Request = c.Request.WithContext(auth.WithIdentity(c.Request.Context(), identity))
+		}
+	})
+	router.POST("/comments", Require(auth.PermCreate), func(c *ginext.Context) { c.Status(http.StatusNoContent) })
+	router.POST("/export", Require(auth.PermExport), func(c *ginext.Context) { c.Status(http.StatusNoContent) })
+	router.POST("/open/comments", RequireUnauthenticated(auth.PermCreate), func(c *ginext.Context) { c.Status(http.StatusNoContent) })
+	router.POST("/open/moderate", RequireUnauthenticated(auth.PermModerate), func(c *ginext.Context) { c.Status(http.StatusNoContent) })
+
+	for _, tc := range []struct {
+		path, role string
+		code       int
+	}{
+		{"/comments", "", http.StatusForbidden},
+		{"/comments", auth.RoleReader, http.StatusForbidden},
+		{"/comments", auth.RoleCommenter, http.StatusNoContent},
+		{"/comments", "unknown", http.StatusForbidden},
+		{"/export", auth.RoleModerator, http.StatusForbidden},
+		{"/export", auth.RoleAdmin, http.StatusNoContent},
+		{"/open/comments", "", http.StatusNoContent},
+		{"/open/moderate", "", http.StatusForbidden},
+	} {
+		req := httptest.NewRequest(http.MethodPost, tc.path+"?role="+tc.role, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, tc.code, w.Code, tc.path+" as "+tc.role)
+	}
+
+}
+
+func TestRateLimit(t *testing.T) {
+
+	limits := []config.RateLimit{
+		{Method: http.MethodPost, Path: "/comments/:id/vote", Requests: 1, Per: time.Minute, Burst: 2},
+		{Method: http.MethodPost, Path: "/comments", Requests: 0, Per: time.Minute},
+	}
+
+	router := ginext.New("")
+	router.Use(func(c *ginext.Context) {
+		if subject := c.Query("as"); subject != "" {
+			c.Request = c.Request.WithContext(auth.WithIdentity(c.Request.Context(), models.Identity{Subject: subject}))
+		}
+	})
+	router.Use(RateLimit(ratelimit.NewLimiter(), limits))
+	router.POST("/comments/:id/vote", func(c *ginext.Context) { c.Status(http.StatusNoContent) })
+	router.POST("/comments", func(c *ginext.Context) { c.Status(http.StatusNoContent) })
+
+	send := func(path, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("burst then throttle", func(t *testing.T) {
+		w := send("/comments/1/vote", "192.0.2.1:1000")
+		require.Equal(t, http.StatusNoContent, w.Code)
+		require.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+		require.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+		require.Equal(t, "60", w.Header().Get("RateLimit-Reset"))
+
+		// The bucket is per route, not per comment.
+		w = send("/comments/2/vote", "192.0.2.1:1001")
+		require.Equal(t, http.StatusNoContent, w.Code)
+		require.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+
+		w = send("/comments/1/vote", "192.0.2.1:1002")
+		require.Equal(t, http.StatusTooManyRequests, w.Code)
+		require.Equal(t, "60", w.Header().Get("Retry-After"))
+		require.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+		require.Contains(t, w.Body.String(), errs.ErrRateLimited.Error())
+	})
+
+	t.Run("clients have separate buckets", func(t *testing.T) {
+		require.Equal(t, http.StatusNoContent, send("/comments/1/vote", "192.0.2.2:1000").Code)
+		require.Equal(t, http.StatusNoContent, send("/comments/1/vote?as=alice", "192.0.2.1:1000").Code)
+		require.Equal(t, http.StatusNoContent, send("/comments/1/vote?as=bob", "192.0.2.1:1000").Code)
+	})
+
+	t.Run("unlimited routes", func(t *testing.T) {
+		for range 3 {
+			w := send("/comments", "192.0.2.1:1000")
+			require.Equal(t, http.StatusNoContent, w.Code)
+			require.Empty(t, w.Header().Get("RateLimit-Limit"))
+		}
+	})
+
+}
+
+func TestThrottleCredentials(t *testing.T) {
+
+	router := ginext.New("")
+	router.Use(ThrottleCredentials(ratelimit.NewLimiter(), config.RateLimit{Requests: 1, Per: time.Minute, Burst: 2}))
+	router.Use(func(c *ginext.Context) {
+		if c.GetHeader(apiKeyHeader) != "" {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Next()
+	})
+	router.GET("/comments", func(c *ginext.Context) { c.Status(http.StatusNoContent) })
+
+	send := func(remoteAddr, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/comments", nil)
+		req.RemoteAddr = remoteAddr
+		if apiKey != "" {
+			req.Header.Set(apiKeyHeader, apiKey)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("failed guesses are throttled per client", func(t *testing.T) {
+		require.Equal(t, http.StatusUnauthorized, send("192.0.2.1:1000", "guess-1").Code)
+		require.Equal(t, http.StatusUnauthorized, send("192.0.2.1:1001", "guess-2").Code)
+
+		w := send("192.0.2.1:1002", "guess-3")
+		require.Equal(t, http.StatusTooManyRequests, w.Code)
+		require.Equal(t, "60", w.Header().Get("Retry-After"))
+
+		require.Equal(t, http.StatusUnauthorized, send("192.0.2.2:1000", "guess-4").Code)
+	})
+
+	t.Run("requests without credentials are not counted", func(t *testing.T) {
+		for range 3 {
+			w := send("192.0.2.1:1000", "")
+			require.Equal(t, http.StatusNoContent, w.Code)
+			require.Empty(t, w.Header().Get("RateLimit-Limit"))
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		router := ginext.New("")
+		router.Use(ThrottleCredentials(ratelimit.NewLimiter(), config.RateLimit{}))
+		router.GET("/comments", func(c *ginext.Context) { c.Status(http.StatusNoContent) })
+		for range 3 {
+			req := httptest.NewRequest(http.MethodGet, "/comments", nil)
+			req.Header.Set("Authorization", "Bearer x")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			require.Equal(t, http.StatusNoContent, w.Code)
+		}
 	})
 
 }
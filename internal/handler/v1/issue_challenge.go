@@ -0,0 +1,17 @@
+package v1
+
+import (
+	"github.com/wb-go/wbf/ginext"
+)
+
+func (h *Handler) IssueChallenge(c *ginext.Context) {
+
+	challenge, err := h.service.IssueChallenge(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondOK(c, challenge)
+
+}
@@ -0,0 +1,17 @@
+package v1
+
+import (
+	"github.com/wb-go/wbf/ginext"
+)
+
+func (h *Handler) ListAPIKeys(c *ginext.Context) {
+
+	keys, err := h.service.ListAPIKeys(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondOK(c, keys)
+
+}
@@ -0,0 +1,13 @@
+package v1
+
+import (
+	"github.com/wb-go/wbf/ginext"
+)
+
+func (h *Handler) LockComment(c *ginext.Context) {
+	h.moderate(c, h.service.LockComment)
+}
+
+func (h *Handler) UnlockComment(c *ginext.Context) {
+	h.moderate(c, h.service.UnlockComment)
+}
@@ -0,0 +1,34 @@
+package v1
+
+import (
+	"Hermes/internal/models"
+	"context"
+
+	"github.com/wb-go/wbf/ginext"
+)
+
+func (h *Handler) ApproveComment(c *ginext.Context) {
+	h.moderate(c, h.service.ApproveComment)
+}
+
+func (h *Handler) RejectComment(c *ginext.Context) {
+	h.moderate(c, h.service.RejectComment)
+}
+
+func (h *Handler) moderate(c *ginext.Context, apply func(ctx context.Context, id int64) (models.Comment, error)) {
+
+	id, err := parseParam(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	comment, err := apply(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondOK(c, comment)
+
+}
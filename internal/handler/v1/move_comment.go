@@ -0,0 +1,59 @@
+package v1
+
+import (
+	"Hermes/internal/errs"
+	"encoding/json"
+
+	"github.com/wb-go/wbf/ginext"
+)
+
+func (h *Handler) MoveComment(c *ginext.Context) {
+
+	id, err := parseParam(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var request MoveCommentV1
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, errs.ErrInvalidJSON)
+		return
+	}
+
+	parentID, err := parseMoveParent(request.ParentID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	comment, err := h.service.MoveComment(c.Request.Context(), id, parentID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondOK(c, comment)
+
+}
+
+// parseMoveParent reads the new parent ID, nil for an explicit null.
+func parseMoveParent(raw json.RawMessage) (*int64, error) {
+
+	if len(raw) == 0 {
+		return nil, errs.ErrInvalidParentID
+	}
+
+	if string(raw) == "null" {
+		return nil, nil
+	}
+
+	var parentID int64
+	if err := json.Unmarshal(raw, &parentID); err != nil {
+		return nil, errs.ErrInvalidParentID
+	}
+
+	return &parentID, nil
+
+}
@@ -0,0 +1,13 @@
+package v1
+
+import (
+	"github.com/wb-go/wbf/ginext"
+)
+
+func (h *Handler) PinComment(c *ginext.Context) {
+	h.moderate(c, h.service.PinComment)
+}
+
+func (h *Handler) UnpinComment(c *ginext.Context) {
+	h.moderate(c, h.service.UnpinComment)
+}
@@ -0,0 +1,104 @@
+package v1
+
+import (
+	"Hermes/internal/auth"
+	"Hermes/internal/config"
+	"Hermes/internal/errs"
+	"Hermes/internal/ratelimit"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/wb-go/wbf/ginext"
+)
+
+// RateLimit throttles the routes listed in limits. Each client gets its own
+// bucket per route: API keys and users by their subject, anonymous callers
+// by IP address. Limits without requests or a period are ignored. It must
+// run after Authenticate.
+func RateLimit(limiter ratelimit.Limiter, limits []config.RateLimit) ginext.HandlerFunc {
+
+	routes := make(map[string]config.RateLimit, len(limits))
+	for _, limit := range limits {
+		if limit.Requests <= 0 || limit.Per <= 0 {
+			continue
+		}
+		routes[limit.Method+" "+limit.Path] = limit
+	}
+
+	return func(c *ginext.Context) {
+
+		route := c.Request.Method + " " + c.FullPath()
+
+		limit, ok := routes[route]
+		if !ok {
+			c.Next()
+			return
+		}
+
+		if !throttle(c, limiter, route+" "+client(c), limit) {
+			return
+		}
+
+		c.Next()
+
+	}
+
+}
+
+// ThrottleCredentials limits requests carrying a bearer token or an API key
+// per client IP. It must run before Authenticate, so that guessing keys or
+// tokens is throttled even though failed attempts never reach RateLimit.
+// A limit without requests or a period disables it.
+func ThrottleCredentials(limiter ratelimit.Limiter, limit config.RateLimit) ginext.HandlerFunc {
+	return func(c *ginext.Context) {
+
+		if limit.Requests <= 0 || limit.Per <= 0 || (c.GetHeader(apiKeyHeader) == "" && c.GetHeader("Authorization") == "") {
+			c.Next()
+			return
+		}
+
+		if !throttle(c, limiter, "credentials ip:"+c.ClientIP(), limit) {
+			return
+		}
+
+		c.Next()
+
+	}
+}
+
+// throttle takes a token from key's bucket and sets the RateLimit headers.
+// It aborts the request and returns false when the bucket is empty.
+func throttle(c *ginext.Context, limiter ratelimit.Limiter, key string, limit config.RateLimit) bool {
+
+	status, err := limiter.Allow(c.Request.Context(), key, limit)
+	if err != nil {
+		respondError(c, err)
+		return false
+	}
+
+	c.Header("RateLimit-Limit", strconv.Itoa(status.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(status.Remaining))
+	c.Header("RateLimit-Reset", ceilSeconds(status.Reset))
+
+	if !status.Allowed {
+		c.Header("Retry-After", ceilSeconds(status.RetryAfter))
+		respondError(c, errs.ErrRateLimited)
+		return false
+	}
+
+	return true
+
+}
+
+// client identifies the caller for rate limiting.
+func client(c *ginext.Context) string {
+	if identity, ok := auth.IdentityFromContext(c.Request.Context()); ok {
+		return "sub:" + identity.Subject
+	}
+	return "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,41 @@
+package v1
+
+import (
+	"Hermes/internal/errs"
+	"context"
+
+	"github.com/wb-go/wbf/ginext"
+)
+
+func (h *Handler) AddReaction(c *ginext.Context) {
+	h.react(c, h.service.AddReaction)
+}
+
+func (h *Handler) RemoveReaction(c *ginext.Context) {
+	h.react(c, h.service.RemoveReaction)
+}
+
+func (h *Handler) react(c *ginext.Context, apply func(ctx context.Context, commentID int64, emoji, reactor string) (map[string]int, error)) {
+
+	id, err := parseParam(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var request ReactionV1
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, errs.ErrInvalidJSON)
+		return
+	}
+
+	counts, err := apply(c.Request.Context(), id, c.Param("emoji"), callerOr(c, request.Reactor))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondOK(c, counts)
+
+}
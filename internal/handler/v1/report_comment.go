@@ -0,0 +1,38 @@
+package v1
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+
+	"github.com/wb-go/wbf/ginext"
+)
+
+func (h *Handler) ReportComment(c *ginext.Context) {
+
+	id, err := parseParam(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var request ReportV1
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, errs.ErrInvalidJSON)
+		return
+	}
+
+	comment, err := h.service.ReportComment(c.Request.Context(), models.Report{
+		CommentID: id,
+		Reporter:  callerOrIP(c),
+		Reason:    request.Reason,
+		Note:      request.Note,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondOK(c, comment)
+
+}
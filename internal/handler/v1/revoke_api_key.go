@@ -0,0 +1,24 @@
+package v1
+
+import (
+	"github.com/wb-go/wbf/ginext"
+)
+
+const revoked = "revoked"
+
+func (h *Handler) RevokeAPIKey(c *ginext.Context) {
+
+	id, err := parseParam(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if err := h.service.RevokeAPIKey(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondOK(c, revoked)
+
+}
@@ -0,0 +1,29 @@
+package v1
+
+import (
+	"github.com/wb-go/wbf/ginext"
+)
+
+func (h *Handler) SearchComments(c *ginext.Context) {
+
+	searchParams, err := parseSearchQuery(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	results, total, err := h.service.SearchComments(c.Request.Context(), searchParams)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondList(c, SearchResponseV1{
+		Result:  results,
+		Total:   total,
+		Page:    searchParams.Page,
+		Limit:   searchParams.Limit,
+		HasMore: searchParams.Offset+len(results) < total,
+	})
+
+}
@@ -0,0 +1,38 @@
+package v1
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+
+	"github.com/wb-go/wbf/ginext"
+)
+
+func (h *Handler) UpdateComment(c *ginext.Context) {
+
+	id, err := parseParam(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var request UpdateCommentV1
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, errs.ErrInvalidJSON)
+		return
+	}
+
+	comment := models.Comment{
+		ID:      id,
+		Content: request.Content,
+	}
+
+	updated, err := h.service.UpdateComment(c.Request.Context(), comment, modifier(c))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondOK(c, updated)
+
+}
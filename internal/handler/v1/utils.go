@@ -6,24 +6,27 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/wb-go/wbf/ginext"
 )
 
 const (
-	defaultPage  = 1
-	defaultLimit = 20
-	defaultSort  = "created_at_desc"
-	reverseSort  = "created_at_asc"
-	maxLimit     = 100
+	defaultPage       = 1
+	defaultLimit      = 20
+	defaultSort       = "created_at_desc"
+	reverseSort       = "created_at_asc"
+	topSort           = "top"
+	controversialSort = "controversial"
+	hotSort           = "hot"
+	maxLimit          = 100
 )
 
 func parseQuery(c *ginext.Context) (models.QueryParams, error) {
 
 	queryParams := models.QueryParams{
-		Page:  defaultPage,
-		Limit: defaultLimit,
-		Sort:  defaultSort,
+		ThreadKey: parseThreadKey(c),
+		Sort:      defaultSort,
 	}
 
 	if val := c.Query("parent"); val != "" {
@@ -34,37 +37,134 @@ func parseQuery(c *ginext.Context) (models.QueryParams, error) {
 		queryParams.ParentID = &parentID
 	}
 
+	page, limit, err := parsePagination(c)
+	if err != nil {
+		return models.QueryParams{}, err
+	}
+	queryParams.Page, queryParams.Limit = page, limit
+
+	limits, err := parseTreeLimits(c)
+	if err != nil {
+		return models.QueryParams{}, err
+	}
+	queryParams.TreeLimits = limits
+
+	sort, err := parseSort(c)
+	if err != nil {
+		return models.QueryParams{}, err
+	}
+	queryParams.Sort = sort
+
+	if val := c.Query("cursor"); val != "" {
+		if !isTimeSort(queryParams.Sort) {
+			return models.QueryParams{}, errs.ErrInvalidCursor
+		}
+		cursor, err := decodeCursor(val)
+		if err != nil {
+			return models.QueryParams{}, err
+		}
+		queryParams.Cursor = cursor
+	}
+
+	if queryParams.Cursor == nil {
+		queryParams.Offset = (queryParams.Page - 1) * queryParams.Limit
+	}
+
+	return queryParams, nil
+
+}
+
+func parseSearchQuery(c *ginext.Context) (models.SearchParams, error) {
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		return models.SearchParams{}, errs.ErrEmptyQuery
+	}
+
+	page, limit, err := parsePagination(c)
+	if err != nil {
+		return models.SearchParams{}, err
+	}
+
+	return models.SearchParams{
+		ThreadKey: parseThreadKey(c),
+		Query:     query,
+		Page:      page,
+		Limit:     limit,
+		Offset:    (page - 1) * limit,
+	}, nil
+
+}
+
+func parseSort(c *ginext.Context) (string, error) {
+	switch val := c.Query("sort"); val {
+	case "":
+		return defaultSort, nil
+	case defaultSort, reverseSort, topSort, controversialSort, hotSort:
+		return val, nil
+	default:
+		return "", errs.ErrInvalidSort
+	}
+}
+
+// isTimeSort reports whether sort orders by creation time, which is the
+// only ordering keyset cursors can page through.
+func isTimeSort(sort string) bool {
+	return sort == defaultSort || sort == reverseSort
+}
+
+func parseThreadKey(c *ginext.Context) string {
+	return strings.TrimSpace(c.Query("thread_key"))
+}
+
+func parsePagination(c *ginext.Context) (int, int, error) {
+
+	page, limit := defaultPage, defaultLimit
+
 	if val := c.Query("page"); val != "" {
-		page, err := strconv.Atoi(val)
-		if err != nil || page < 1 {
-			return models.QueryParams{}, errs.ErrInvalidPage
+		p, err := strconv.Atoi(val)
+		if err != nil || p < 1 {
+			return 0, 0, errs.ErrInvalidPage
 		}
-		queryParams.Page = page
+		page = p
 	}
 
 	if val := c.Query("limit"); val != "" {
-		limit, err := strconv.Atoi(val)
-		if err != nil || limit < 1 {
-			return models.QueryParams{}, errs.ErrInvalidLimit
+		l, err := strconv.Atoi(val)
+		if err != nil || l < 1 {
+			return 0, 0, errs.ErrInvalidLimit
 		}
-		if limit > maxLimit {
-			limit = maxLimit
+		if l > maxLimit {
+			l = maxLimit
 		}
-		queryParams.Limit = limit
+		limit = l
 	}
 
-	if val := c.Query("sort"); val != "" {
-		switch val {
-		case defaultSort, reverseSort:
-			queryParams.Sort = val
-		default:
-			return models.QueryParams{}, errs.ErrInvalidSort
+	return page, limit, nil
+
+}
+
+func parseTreeLimits(c *ginext.Context) (models.TreeLimits, error) {
+
+	var limits models.TreeLimits
+
+	if val := c.Query("max_depth"); val != "" {
+		depth, err := strconv.Atoi(val)
+		if err != nil || depth < 1 {
+			return models.TreeLimits{}, errs.ErrInvalidMaxDepth
 		}
+		limits.MaxDepth = depth
 	}
 
-	queryParams.Offset = (queryParams.Page - 1) * queryParams.Limit
+	if val := c.Query("max_children"); val != "" {
+		children, err := strconv.Atoi(val)
+		if err != nil || children < 1 {
+			return models.TreeLimits{}, errs.ErrInvalidMaxChild
+		}
+		limits.MaxChildren = children
+	}
 
-	return queryParams, nil
+	return limits, nil
 
 }
 
@@ -87,6 +187,10 @@ func respondOK(c *ginext.Context, response any) {
 	c.JSON(http.StatusOK, ginext.H{"result": response})
 }
 
+func respondList(c *ginext.Context, response any) {
+	c.JSON(http.StatusOK, response)
+}
+
 func respondError(c *ginext.Context, err error) {
 	if err != nil {
 		status, msg := mapErrorToStatus(err)
@@ -105,11 +209,53 @@ func mapErrorToStatus(err error) (int, string) {
 		errors.Is(err, errs.ErrInvalidLimit),
 		errors.Is(err, errs.ErrEmptyCommentID),
 		errors.Is(err, errs.ErrInvalidCommentID),
-		errors.Is(err, errs.ErrInvalidSort):
+		errors.Is(err, errs.ErrInvalidSort),
+		errors.Is(err, errs.ErrInvalidCursor),
+		errors.Is(err, errs.ErrEmptyQuery),
+		errors.Is(err, errs.ErrInvalidMaxDepth),
+		errors.Is(err, errs.ErrInvalidMaxChild),
+		errors.Is(err, errs.ErrInvalidInclude),
+		errors.Is(err, errs.ErrInvalidThreadKey),
+		errors.Is(err, errs.ErrEmptyVoter),
+		errors.Is(err, errs.ErrInvalidVote),
+		errors.Is(err, errs.ErrEmptyReactor),
+		errors.Is(err, errs.ErrInvalidReaction),
+		errors.Is(err, errs.ErrInvalidKeyName),
+		errors.Is(err, errs.ErrInvalidScope),
+		errors.Is(err, errs.ErrEmptyReporter),
+		errors.Is(err, errs.ErrInvalidReason),
+		errors.Is(err, errs.ErrNoteTooLong),
+		errors.Is(err, errs.ErrProofRequired),
+		errors.Is(err, errs.ErrInvalidProof),
+		errors.Is(err, errs.ErrProofReused),
+		errors.Is(err, errs.ErrNotRootComment),
+		errors.Is(err, errs.ErrInvalidMove):
 		return http.StatusBadRequest, err.Error()
 
+	case errors.Is(err, errs.ErrBannedWords),
+		errors.Is(err, errs.ErrTooManyLinks),
+		errors.Is(err, errs.ErrRepeatedChars):
+		return http.StatusUnprocessableEntity, err.Error()
+
+	case errors.Is(err, errs.ErrDuplicateComment),
+		errors.Is(err, errs.ErrKeyNameTaken):
+		return http.StatusConflict, err.Error()
+
+	case errors.Is(err, errs.ErrThreadLocked):
+		return http.StatusLocked, err.Error()
+
+	case errors.Is(err, errs.ErrRateLimited):
+		return http.StatusTooManyRequests, err.Error()
+
+	case errors.Is(err, errs.ErrUnauthorized):
+		return http.StatusUnauthorized, err.Error()
+
+	case errors.Is(err, errs.ErrForbidden):
+		return http.StatusForbidden, err.Error()
+
 	case errors.Is(err, errs.ErrParentNotFound),
-		errors.Is(err, errs.ErrCommentNotFound):
+		errors.Is(err, errs.ErrCommentNotFound),
+		errors.Is(err, errs.ErrAPIKeyNotFound):
 		return http.StatusNotFound, err.Error()
 
 	default:
@@ -0,0 +1,32 @@
+package v1
+
+import (
+	"Hermes/internal/errs"
+
+	"github.com/wb-go/wbf/ginext"
+)
+
+func (h *Handler) VoteComment(c *ginext.Context) {
+
+	id, err := parseParam(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	var request VoteV1
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		respondError(c, errs.ErrInvalidJSON)
+		return
+	}
+
+	comment, err := h.service.VoteComment(c.Request.Context(), id, callerOrIP(c), request.Value)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondOK(c, comment)
+
+}
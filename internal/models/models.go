@@ -2,20 +2,161 @@ package models
 
 import "time"
 
+// Moderation statuses of a comment. Only approved comments are public;
+// hidden ones were taken down automatically after too many reports.
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusRejected = "rejected"
+	StatusHidden   = "hidden"
+)
+
+// Reason codes a comment can be reported for.
+const (
+	ReasonSpam       = "spam"
+	ReasonHarassment = "harassment"
+	ReasonHateSpeech = "hate-speech"
+	ReasonOffTopic   = "off-topic"
+	ReasonOther      = "other"
+)
+
 type Comment struct {
 	ID        int64      `json:"id"`
 	ParentID  *int64     `json:"parent_id,omitempty"`
+	ThreadKey string     `json:"thread_key"`
 	Content   string     `json:"content"`
 	Author    string     `json:"author"`
+	Status    string     `json:"status"`
+	Score     int        `json:"score"`
+	Upvotes   int        `json:"upvotes"`
+	Downvotes int        `json:"downvotes"`
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	LockedAt  *time.Time `json:"locked_at,omitempty"`
+	Pinned    bool       `json:"pinned"`
+	PinnedAt  *time.Time `json:"pinned_at,omitempty"`
 	Children  []*Comment `json:"children,omitempty"`
+
+	Reactions map[string]int `json:"reactions,omitempty"`
+
+	ChildrenCount   int  `json:"children_count"`
+	HasMoreChildren bool `json:"has_more_children,omitempty"`
+}
+
+// Report is a user's flag on a comment. Each reporter counts once per comment.
+type Report struct {
+	CommentID int64
+	Reporter  string
+	Reason    string
+	Note      string
+}
+
+// ReportedComment is a comment in the moderators' report listing together
+// with how often, and why, it was reported.
+type ReportedComment struct {
+	Comment
+	Reports        int            `json:"reports"`
+	Reasons        map[string]int `json:"reasons"`
+	LastReportedAt time.Time      `json:"last_reported_at"`
+}
+
+// Challenge is a proof-of-work puzzle: find a Nonce such that the SHA-256
+// of Challenge + ":" + Nonce starts with Difficulty zero bits. Required is
+// false when posting needs no proof.
+type Challenge struct {
+	Required   bool      `json:"required"`
+	Challenge  string    `json:"challenge,omitempty"`
+	Difficulty int       `json:"difficulty,omitempty"`
+	ExpiresAt  time.Time `json:"expires_at,omitzero"`
+}
+
+// Proof is a solved Challenge sent along with a new comment.
+type Proof struct {
+	Challenge string
+	Nonce     string
+}
+
+// Identity is the authenticated caller of a request. Users carry roles;
+// API keys carry the scopes they were issued with instead.
+type Identity struct {
+	Subject string
+	Roles   []string
+	Scopes  []string
+	// AuthorToken is the token an anonymous caller got when creating the
+	// comment being modified, used when authentication is disabled.
+	AuthorToken string
+}
+
+// APIKey describes a key issued to a server-to-server integration. Only a
+// hash of the key itself is stored; Prefix identifies it in listings.
+type APIKey struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+}
+
+// RateLimitStatus is a rate limiter's decision on one request. Remaining
+// is the number of requests left in the bucket; Reset is the time until it
+// is full again, and RetryAfter the time until the next request is allowed.
+type RateLimitStatus struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
 }
 
 type QueryParams struct {
-	ParentID *int64
-	Page     int
-	Limit    int
-	Sort     string
-	Offset   int
+	ThreadKey string
+	ParentID  *int64
+	Page      int
+	Limit     int
+	Sort      string
+	Offset    int
+	Cursor    *Cursor
+	TreeLimits
+}
+
+// TreeLimits bounds how much of a comment tree is loaded. Zero means no limit.
+type TreeLimits struct {
+	MaxDepth    int
+	MaxChildren int
+}
+
+type SearchParams struct {
+	ThreadKey string
+	Query     string
+	Page      int
+	Limit     int
+	Offset    int
+}
+
+// SearchResult is a matched comment together with the IDs of its ancestors,
+// ordered from the thread root down to the direct parent.
+type SearchResult struct {
+	Comment
+	Rank      float64 `json:"rank"`
+	Ancestors []int64 `json:"ancestors"`
+}
+
+// Cursor is the keyset position of the last root comment on a page.
+// PinnedAt is set while the page still ends among the pinned comments.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        int64
+	PinnedAt  *time.Time
+}
+
+type Revision struct {
+	ID        int64     `json:"id"`
+	CommentID int64     `json:"comment_id"`
+	Content   string    `json:"content"`
+	Author    string    `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
 }
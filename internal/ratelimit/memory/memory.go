@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"Hermes/internal/config"
+	"Hermes/internal/models"
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often buckets that have refilled are dropped.
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	full    time.Time
+}
+
+type Limiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewLimiter() *Limiter {
+	return &Limiter{buckets: make(map[string]*bucket), now: time.Now}
+}
+
+func (l *Limiter) Allow(_ context.Context, key string, limit config.RateLimit) (models.RateLimitStatus, error) {
+
+	capacity := float64(limit.Burst)
+	if limit.Burst <= 0 {
+		capacity = float64(limit.Requests)
+	}
+	rate := float64(limit.Requests) / limit.Per.Seconds()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = math.Min(capacity, b.tokens+now.Sub(b.updated).Seconds()*rate)
+	b.updated = now
+
+	status := models.RateLimitStatus{Limit: int(capacity)}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		status.Allowed = true
+	} else {
+		status.RetryAfter = seconds((1 - b.tokens) / rate)
+	}
+
+	status.Remaining = int(b.tokens)
+	status.Reset = seconds((capacity - b.tokens) / rate)
+	b.full = now.Add(status.Reset)
+
+	return status, nil
+
+}
+
+// sweep drops buckets that are full again, since a new bucket starts full.
+func (l *Limiter) sweep(now time.Time) {
+
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if !now.Before(b.full) {
+			delete(l.buckets, key)
+		}
+	}
+
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"Hermes/internal/config"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestLimiter() (*Limiter, *clock) {
+	c := &clock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	l := NewLimiter()
+	l.now = func() time.Time { return c.now }
+	return l, c
+}
+
+func TestLimiter_Allow(t *testing.T) {
+
+	ctx := context.Background()
+	limit := config.RateLimit{Requests: 6, Per: time.Minute, Burst: 2}
+
+	t.Run("burst then refill", func(t *testing.T) {
+
+		l, c := newTestLimiter()
+
+		for i := 0; i < 2; i++ {
+			status, err := l.Allow(ctx, "ip", limit)
+			require.NoError(t, err)
+			require.True(t, status.Allowed)
+			require.Equal(t, 2, status.Limit)
+			require.Equal(t, 1-i, status.Remaining)
+		}
+
+		status, err := l.Allow(ctx, "ip", limit)
+		require.NoError(t, err)
+		require.False(t, status.Allowed)
+		require.Equal(t, 0, status.Remaining)
+		require.Equal(t, 10*time.Second, status.RetryAfter)
+		require.Equal(t, 20*time.Second, status.Reset)
+
+		c.advance(5 * time.Second)
+		status, _ = l.Allow(ctx, "ip", limit)
+		require.False(t, status.Allowed)
+		require.Equal(t, 5*time.Second, status.RetryAfter)
+		require.Equal(t, 15*time.Second, status.Reset)
+
+		c.advance(5 * time.Second)
+		status, _ = l.Allow(ctx, "ip", limit)
+		require.True(t, status.Allowed)
+		require.Equal(t, time.Duration(0), status.RetryAfter)
+		require.Equal(t, 20*time.Second, status.Reset)
+
+	})
+
+	t.Run("refill is capped at burst", func(t *testing.T) {
+
+		l, c := newTestLimiter()
+
+		l.Allow(ctx, "ip", limit)
+		c.advance(time.Hour)
+
+		for i := 0; i < 2; i++ {
+			status, _ := l.Allow(ctx, "ip", limit)
+			require.True(t, status.Allowed)
+		}
+		status, _ := l.Allow(ctx, "ip", limit)
+		require.False(t, status.Allowed)
+
+	})
+
+	t.Run("burst defaults to requests", func(t *testing.T) {
+
+		l, _ := newTestLimiter()
+
+		status, _ := l.Allow(ctx, "ip", config.RateLimit{Requests: 3, Per: time.Minute})
+		require.True(t, status.Allowed)
+		require.Equal(t, 3, status.Limit)
+		require.Equal(t, 2, status.Remaining)
+		require.Equal(t, 20*time.Second, status.Reset)
+
+	})
+
+	t.Run("keys have separate buckets", func(t *testing.T) {
+
+		l, _ := newTestLimiter()
+
+		l.Allow(ctx, "a", limit)
+		l.Allow(ctx, "a", limit)
+		status, _ := l.Allow(ctx, "a", limit)
+		require.False(t, status.Allowed)
+
+		status, _ = l.Allow(ctx, "b", limit)
+		require.True(t, status.Allowed)
+
+	})
+
+}
+
+func TestLimiter_Sweep(t *testing.T) {
+
+	ctx := context.Background()
+	limit := config.RateLimit{Requests: 6, Per: time.Minute, Burst: 2}
+
+	l, c := newTestLimiter()
+
+	// Both buckets are full again 10s after one request.
+	l.Allow(ctx, "idle", limit)
+	l.Allow(ctx, "busy", limit)
+
+	// Sweeps run at most once per interval.
+	c.advance(sweepInterval - 5*time.Second)
+	l.Allow(ctx, "busy", limit)
+	l.Allow(ctx, "busy", limit)
+	require.Len(t, l.buckets, 2)
+
+	// The full bucket is dropped; the one still refilling keeps its tokens.
+	c.advance(5 * time.Second)
+	status, _ := l.Allow(ctx, "busy", limit)
+	require.False(t, status.Allowed)
+	require.Len(t, l.buckets, 1)
+	require.Contains(t, l.buckets, "busy")
+
+	c.advance(sweepInterval)
+	l.Allow(ctx, "other", limit)
+	require.NotContains(t, l.buckets, "busy")
+
+}
@@ -0,0 +1,20 @@
+package ratelimit
+
+import (
+	"Hermes/internal/config"
+	"Hermes/internal/models"
+	"Hermes/internal/ratelimit/memory"
+	"context"
+)
+
+// Limiter keeps one token bucket per key and takes a token from it for
+// every request. Implementations may share buckets between instances.
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit config.RateLimit) (models.RateLimitStatus, error)
+}
+
+// NewLimiter creates an in-process Limiter. Buckets are not shared between
+// instances of the service.
+func NewLimiter() Limiter {
+	return memory.NewLimiter()
+}
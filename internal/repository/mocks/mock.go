@@ -8,6 +8,7 @@ import (
 	models "Hermes/internal/models"
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -35,6 +36,21 @@ func (m *MockStorage) EXPECT() *MockStorageMockRecorder {
 	return m.recorder
 }
 
+// AddReaction mocks base method.
+func (m *MockStorage) AddReaction(ctx context.Context, commentID int64, emoji, reactor string) (map[string]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReaction", ctx, commentID, emoji, reactor)
+	ret0, _ := ret[0].(map[string]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddReaction indicates an expected call of AddReaction.
+func (mr *MockStorageMockRecorder) AddReaction(ctx, commentID, emoji, reactor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReaction", reflect.TypeOf((*MockStorage)(nil).AddReaction), ctx, commentID, emoji, reactor)
+}
+
 // Close mocks base method.
 func (m *MockStorage) Close() {
 	m.ctrl.T.Helper()
@@ -47,6 +63,51 @@ func (mr *MockStorageMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockStorage)(nil).Close))
 }
 
+// CountRecentComments mocks base method.
+func (m *MockStorage) CountRecentComments(ctx context.Context, window time.Duration) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRecentComments", ctx, window)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRecentComments indicates an expected call of CountRecentComments.
+func (mr *MockStorageMockRecorder) CountRecentComments(ctx, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRecentComments", reflect.TypeOf((*MockStorage)(nil).CountRecentComments), ctx, window)
+}
+
+// CountRootComments mocks base method.
+func (m *MockStorage) CountRootComments(ctx context.Context, queryParams models.QueryParams) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRootComments", ctx, queryParams)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRootComments indicates an expected call of CountRootComments.
+func (mr *MockStorageMockRecorder) CountRootComments(ctx, queryParams interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRootComments", reflect.TypeOf((*MockStorage)(nil).CountRootComments), ctx, queryParams)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockStorage) CreateAPIKey(ctx context.Context, key models.APIKey, hash string) (models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, key, hash)
+	ret0, _ := ret[0].(models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockStorageMockRecorder) CreateAPIKey(ctx, key, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockStorage)(nil).CreateAPIKey), ctx, key, hash)
+}
+
 // CreateComment mocks base method.
 func (m *MockStorage) CreateComment(ctx context.Context, comment models.Comment) (int64, error) {
 	m.ctrl.T.Helper()
@@ -76,19 +137,127 @@ func (mr *MockStorageMockRecorder) DeleteComment(ctx, id interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockStorage)(nil).DeleteComment), ctx, id)
 }
 
-// GetCommentTree mocks base method.
-func (m *MockStorage) GetCommentTree(ctx context.Context, id int64) ([]models.Comment, error) {
+// GetAncestors mocks base method.
+func (m *MockStorage) GetAncestors(ctx context.Context, id int64) ([]models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAncestors", ctx, id)
+	ret0, _ := ret[0].([]models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAncestors indicates an expected call of GetAncestors.
+func (mr *MockStorageMockRecorder) GetAncestors(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAncestors", reflect.TypeOf((*MockStorage)(nil).GetAncestors), ctx, id)
+}
+
+// GetChildren mocks base method.
+func (m *MockStorage) GetChildren(ctx context.Context, parentID int64, queryParams models.QueryParams) ([]models.Comment, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChildren", ctx, parentID, queryParams)
+	ret0, _ := ret[0].([]models.Comment)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetChildren indicates an expected call of GetChildren.
+func (mr *MockStorageMockRecorder) GetChildren(ctx, parentID, queryParams interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChildren", reflect.TypeOf((*MockStorage)(nil).GetChildren), ctx, parentID, queryParams)
+}
+
+// GetComment mocks base method.
+func (m *MockStorage) GetComment(ctx context.Context, id int64) (models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComment", ctx, id)
+	ret0, _ := ret[0].(models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetComment indicates an expected call of GetComment.
+func (mr *MockStorageMockRecorder) GetComment(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComment", reflect.TypeOf((*MockStorage)(nil).GetComment), ctx, id)
+}
+
+// GetCommentTrees mocks base method.
+func (m *MockStorage) GetCommentTrees(ctx context.Context, rootIDs []int64, sort string, limits models.TreeLimits) ([]models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommentTrees", ctx, rootIDs, sort, limits)
+	ret0, _ := ret[0].([]models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommentTrees indicates an expected call of GetCommentTrees.
+func (mr *MockStorageMockRecorder) GetCommentTrees(ctx, rootIDs, sort, limits interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommentTrees", reflect.TypeOf((*MockStorage)(nil).GetCommentTrees), ctx, rootIDs, sort, limits)
+}
+
+// GetPendingComments mocks base method.
+func (m *MockStorage) GetPendingComments(ctx context.Context, queryParams models.QueryParams) ([]models.Comment, int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCommentTree", ctx, id)
+	ret := m.ctrl.Call(m, "GetPendingComments", ctx, queryParams)
 	ret0, _ := ret[0].([]models.Comment)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPendingComments indicates an expected call of GetPendingComments.
+func (mr *MockStorageMockRecorder) GetPendingComments(ctx, queryParams interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingComments", reflect.TypeOf((*MockStorage)(nil).GetPendingComments), ctx, queryParams)
+}
+
+// GetReactions mocks base method.
+func (m *MockStorage) GetReactions(ctx context.Context, ids []int64) (map[int64]map[string]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReactions", ctx, ids)
+	ret0, _ := ret[0].(map[int64]map[string]int)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetCommentTree indicates an expected call of GetCommentTree.
-func (mr *MockStorageMockRecorder) GetCommentTree(ctx, id interface{}) *gomock.Call {
+// GetReactions indicates an expected call of GetReactions.
+func (mr *MockStorageMockRecorder) GetReactions(ctx, ids interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommentTree", reflect.TypeOf((*MockStorage)(nil).GetCommentTree), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReactions", reflect.TypeOf((*MockStorage)(nil).GetReactions), ctx, ids)
+}
+
+// GetReportedComments mocks base method.
+func (m *MockStorage) GetReportedComments(ctx context.Context, queryParams models.QueryParams) ([]models.ReportedComment, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportedComments", ctx, queryParams)
+	ret0, _ := ret[0].([]models.ReportedComment)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetReportedComments indicates an expected call of GetReportedComments.
+func (mr *MockStorageMockRecorder) GetReportedComments(ctx, queryParams interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportedComments", reflect.TypeOf((*MockStorage)(nil).GetReportedComments), ctx, queryParams)
+}
+
+// GetRevisions mocks base method.
+func (m *MockStorage) GetRevisions(ctx context.Context, commentID int64) ([]models.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisions", ctx, commentID)
+	ret0, _ := ret[0].([]models.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisions indicates an expected call of GetRevisions.
+func (mr *MockStorageMockRecorder) GetRevisions(ctx, commentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisions", reflect.TypeOf((*MockStorage)(nil).GetRevisions), ctx, commentID)
 }
 
 // GetRootComments mocks base method.
@@ -105,3 +274,272 @@ func (mr *MockStorageMockRecorder) GetRootComments(ctx, queryParams interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRootComments", reflect.TypeOf((*MockStorage)(nil).GetRootComments), ctx, queryParams)
 }
+
+// GetUserRole mocks base method.
+func (m *MockStorage) GetUserRole(ctx context.Context, subject string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRole", ctx, subject)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRole indicates an expected call of GetUserRole.
+func (mr *MockStorageMockRecorder) GetUserRole(ctx, subject interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRole", reflect.TypeOf((*MockStorage)(nil).GetUserRole), ctx, subject)
+}
+
+// HasRecentDuplicate mocks base method.
+func (m *MockStorage) HasRecentDuplicate(ctx context.Context, author, content string, window time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasRecentDuplicate", ctx, author, content, window)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasRecentDuplicate indicates an expected call of HasRecentDuplicate.
+func (mr *MockStorageMockRecorder) HasRecentDuplicate(ctx, author, content, window interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasRecentDuplicate", reflect.TypeOf((*MockStorage)(nil).HasRecentDuplicate), ctx, author, content, window)
+}
+
+// ListAPIKeys mocks base method.
+func (m *MockStorage) ListAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeys", ctx)
+	ret0, _ := ret[0].([]models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockStorageMockRecorder) ListAPIKeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockStorage)(nil).ListAPIKeys), ctx)
+}
+
+// LockComment mocks base method.
+func (m *MockStorage) LockComment(ctx context.Context, id int64) (models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockComment", ctx, id)
+	ret0, _ := ret[0].(models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockComment indicates an expected call of LockComment.
+func (mr *MockStorageMockRecorder) LockComment(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockComment", reflect.TypeOf((*MockStorage)(nil).LockComment), ctx, id)
+}
+
+// LockInactiveThreads mocks base method.
+func (m *MockStorage) LockInactiveThreads(ctx context.Context, inactivity time.Duration) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockInactiveThreads", ctx, inactivity)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockInactiveThreads indicates an expected call of LockInactiveThreads.
+func (mr *MockStorageMockRecorder) LockInactiveThreads(ctx, inactivity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockInactiveThreads", reflect.TypeOf((*MockStorage)(nil).LockInactiveThreads), ctx, inactivity)
+}
+
+// ModerateComment mocks base method.
+func (m *MockStorage) ModerateComment(ctx context.Context, id int64, status string) (models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModerateComment", ctx, id, status)
+	ret0, _ := ret[0].(models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ModerateComment indicates an expected call of ModerateComment.
+func (mr *MockStorageMockRecorder) ModerateComment(ctx, id, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModerateComment", reflect.TypeOf((*MockStorage)(nil).ModerateComment), ctx, id, status)
+}
+
+// MoveComment mocks base method.
+func (m *MockStorage) MoveComment(ctx context.Context, id int64, parentID *int64) (models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveComment", ctx, id, parentID)
+	ret0, _ := ret[0].(models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveComment indicates an expected call of MoveComment.
+func (mr *MockStorageMockRecorder) MoveComment(ctx, id, parentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveComment", reflect.TypeOf((*MockStorage)(nil).MoveComment), ctx, id, parentID)
+}
+
+// PinComment mocks base method.
+func (m *MockStorage) PinComment(ctx context.Context, id int64) (models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinComment", ctx, id)
+	ret0, _ := ret[0].(models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PinComment indicates an expected call of PinComment.
+func (mr *MockStorageMockRecorder) PinComment(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinComment", reflect.TypeOf((*MockStorage)(nil).PinComment), ctx, id)
+}
+
+// RemoveReaction mocks base method.
+func (m *MockStorage) RemoveReaction(ctx context.Context, commentID int64, emoji, reactor string) (map[string]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveReaction", ctx, commentID, emoji, reactor)
+	ret0, _ := ret[0].(map[string]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveReaction indicates an expected call of RemoveReaction.
+func (mr *MockStorageMockRecorder) RemoveReaction(ctx, commentID, emoji, reactor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveReaction", reflect.TypeOf((*MockStorage)(nil).RemoveReaction), ctx, commentID, emoji, reactor)
+}
+
+// ReportComment mocks base method.
+func (m *MockStorage) ReportComment(ctx context.Context, report models.Report, threshold int) (models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportComment", ctx, report, threshold)
+	ret0, _ := ret[0].(models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportComment indicates an expected call of ReportComment.
+func (mr *MockStorageMockRecorder) ReportComment(ctx, report, threshold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportComment", reflect.TypeOf((*MockStorage)(nil).ReportComment), ctx, report, threshold)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockStorage) RevokeAPIKey(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockStorageMockRecorder) RevokeAPIKey(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockStorage)(nil).RevokeAPIKey), ctx, id)
+}
+
+// SearchComments mocks base method.
+func (m *MockStorage) SearchComments(ctx context.Context, searchParams models.SearchParams) ([]models.SearchResult, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchComments", ctx, searchParams)
+	ret0, _ := ret[0].([]models.SearchResult)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchComments indicates an expected call of SearchComments.
+func (mr *MockStorageMockRecorder) SearchComments(ctx, searchParams interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchComments", reflect.TypeOf((*MockStorage)(nil).SearchComments), ctx, searchParams)
+}
+
+// UnlockComment mocks base method.
+func (m *MockStorage) UnlockComment(ctx context.Context, id int64) (models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockComment", ctx, id)
+	ret0, _ := ret[0].(models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnlockComment indicates an expected call of UnlockComment.
+func (mr *MockStorageMockRecorder) UnlockComment(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockComment", reflect.TypeOf((*MockStorage)(nil).UnlockComment), ctx, id)
+}
+
+// UnpinComment mocks base method.
+func (m *MockStorage) UnpinComment(ctx context.Context, id int64) (models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnpinComment", ctx, id)
+	ret0, _ := ret[0].(models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnpinComment indicates an expected call of UnpinComment.
+func (mr *MockStorageMockRecorder) UnpinComment(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpinComment", reflect.TypeOf((*MockStorage)(nil).UnpinComment), ctx, id)
+}
+
+// UpdateComment mocks base method.
+func (m *MockStorage) UpdateComment(ctx context.Context, comment models.Comment) (models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateComment", ctx, comment)
+	ret0, _ := ret[0].(models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateComment indicates an expected call of UpdateComment.
+func (mr *MockStorageMockRecorder) UpdateComment(ctx, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateComment", reflect.TypeOf((*MockStorage)(nil).UpdateComment), ctx, comment)
+}
+
+// UseAPIKey mocks base method.
+func (m *MockStorage) UseAPIKey(ctx context.Context, hash string) (models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseAPIKey", ctx, hash)
+	ret0, _ := ret[0].(models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseAPIKey indicates an expected call of UseAPIKey.
+func (mr *MockStorageMockRecorder) UseAPIKey(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseAPIKey", reflect.TypeOf((*MockStorage)(nil).UseAPIKey), ctx, hash)
+}
+
+// UseChallenge mocks base method.
+func (m *MockStorage) UseChallenge(ctx context.Context, id string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseChallenge", ctx, id, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseChallenge indicates an expected call of UseChallenge.
+func (mr *MockStorageMockRecorder) UseChallenge(ctx, id, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseChallenge", reflect.TypeOf((*MockStorage)(nil).UseChallenge), ctx, id, expiresAt)
+}
+
+// VoteComment mocks base method.
+func (m *MockStorage) VoteComment(ctx context.Context, commentID int64, voter string, value int) (models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoteComment", ctx, commentID, voter, value)
+	ret0, _ := ret[0].(models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoteComment indicates an expected call of VoteComment.
+func (mr *MockStorageMockRecorder) VoteComment(ctx, commentID, voter, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoteComment", reflect.TypeOf((*MockStorage)(nil).VoteComment), ctx, commentID, voter, value)
+}
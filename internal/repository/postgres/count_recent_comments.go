@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wb-go/wbf/retry"
+)
+
+// CountRecentComments returns how many comments were posted within window,
+// whatever their status.
+func (s *Storage) CountRecentComments(ctx context.Context, window time.Duration) (int, error) {
+
+	row, err := s.db.QueryRowWithRetry(ctx, retry.Strategy{
+		Attempts: s.config.QueryRetryStrategy.Attempts,
+		Delay:    s.config.QueryRetryStrategy.Delay,
+		Backoff:  s.config.QueryRetryStrategy.Backoff,
+	}, `
+
+		SELECT COUNT(*) FROM comments
+		WHERE created_at > NOW() - MAKE_INTERVAL(secs => $1)`,
+
+		window.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	var count int
+	if err := row.Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	return count, nil
+
+}
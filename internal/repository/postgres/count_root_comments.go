@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"Hermes/internal/models"
+	"context"
+	"fmt"
+
+	"github.com/wb-go/wbf/retry"
+)
+
+// CountRootComments returns how many root comments match params, ignoring pagination.
+func (s *Storage) CountRootComments(ctx context.Context, params models.QueryParams) (int, error) {
+
+	query, args := `
+
+		SELECT COUNT(*) FROM comments
+		WHERE parent_id IS NULL AND thread_key = $1 AND status = 'approved'`, []any{params.ThreadKey}
+
+	if params.ParentID != nil {
+		query, args = `
+
+		SELECT COUNT(*) FROM comments
+		WHERE id = $1 AND thread_key = $2 AND status = 'approved'`, []any{*params.ParentID, params.ThreadKey}
+	}
+
+	row, err := s.db.QueryRowWithRetry(ctx, retry.Strategy{
+		Attempts: s.config.QueryRetryStrategy.Attempts,
+		Delay:    s.config.QueryRetryStrategy.Delay,
+		Backoff:  s.config.QueryRetryStrategy.Backoff,
+	}, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	var total int
+	if err := row.Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	return total, nil
+
+}
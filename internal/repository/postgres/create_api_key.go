@@ -0,0 +1,47 @@
+package postgres
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/wb-go/wbf/retry"
+)
+
+// uniqueViolation is the SQLSTATE of a unique constraint violation.
+const uniqueViolation = "23505"
+
+// CreateAPIKey stores key under hash, the hex SHA-256 of the secret key. A
+// name already used by a live key is rejected with ErrKeyNameTaken.
+func (s *Storage) CreateAPIKey(ctx context.Context, key models.APIKey, hash string) (models.APIKey, error) {
+
+	row, err := s.db.QueryRowWithRetry(ctx, retry.Strategy{
+		Attempts: s.config.QueryRetryStrategy.Attempts,
+		Delay:    s.config.QueryRetryStrategy.Delay,
+		Backoff:  s.config.QueryRetryStrategy.Backoff,
+	}, `
+
+		INSERT INTO api_keys (name, prefix, key_hash, scopes, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+apiKeyColumns,
+
+		key.Name, key.Prefix, hash, pq.Array(key.Scopes), key.CreatedBy)
+	if err != nil {
+		return models.APIKey{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	created, err := scanAPIKey(row)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return models.APIKey{}, errs.ErrKeyNameTaken
+		}
+		return models.APIKey{}, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	return created, nil
+
+}
@@ -1,34 +1,120 @@
 package postgres
 
 import (
+	"Hermes/internal/errs"
 	"Hermes/internal/models"
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
-
-	"github.com/wb-go/wbf/retry"
 )
 
+// CreateComment inserts a comment with the given status, approved if none
+// is set. Replies are only accepted under approved comments and are refused
+// with ErrThreadLocked when the parent or any of its ancestors is locked.
+//
+// The parent row is locked for update and its ancestors for share before
+// the reply is inserted, bottom-up, so a thread can not be locked between
+// the check and the insert.
 func (s *Storage) CreateComment(ctx context.Context, comment models.Comment) (int64, error) {
 
-	row, err := s.db.QueryRowWithRetry(ctx, retry.Strategy{
-		Attempts: s.config.QueryRetryStrategy.Attempts,
-		Delay:    s.config.QueryRetryStrategy.Delay,
-		Backoff:  s.config.QueryRetryStrategy.Backoff}, `
-		
-		INSERT INTO comments (parent_id, content, author)
-		VALUES ($1, $2, $3)
-		RETURNING id`,
+	var id int64
+
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+
+		if comment.ParentID != nil {
+			if err := lockParent(ctx, tx, *comment.ParentID); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.QueryRowContext(ctx, `
+
+			WITH parent AS (
+				UPDATE comments
+				SET reply_count = reply_count + 1
+				WHERE id = $1 AND status = 'approved'
+				RETURNING thread_key
+			)
+			INSERT INTO comments (parent_id, thread_key, content, author, status)
+			SELECT $1, COALESCE((SELECT thread_key FROM parent), $2), $3, $4, COALESCE(NULLIF($5, ''), 'approved')
+			WHERE $1::integer IS NULL OR EXISTS (SELECT 1 FROM parent)
+			RETURNING id`,
+
+			comment.ParentID, comment.ThreadKey, comment.Content, comment.Author, comment.Status).Scan(&id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) { // replies are only accepted under public comments
+				return errs.ErrParentNotFound
+			}
+			return fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		return nil
 
-		comment.ParentID, comment.Content, comment.Author)
+	})
 	if err != nil {
 		return 0, err
 	}
 
-	var id int64
-	if err := row.Scan(&id); err != nil {
-		return 0, fmt.Errorf("failed to scan row: %w", err)
+	return id, nil
+
+}
+
+// lockParent locks an approved parent and its ancestors, failing with
+// ErrThreadLocked if any of them is locked.
+func lockParent(ctx context.Context, tx *sql.Tx, parentID int64) error {
+
+	var lockedAt sql.NullTime
+	if err := tx.QueryRowContext(ctx, `
+
+		SELECT locked_at FROM comments
+		WHERE id = $1 AND status = 'approved'
+		FOR NO KEY UPDATE`,
+
+		parentID).Scan(&lockedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errs.ErrParentNotFound
+		}
+		return fmt.Errorf("failed to lock parent: %w", err)
 	}
 
-	return id, nil
+	if lockedAt.Valid {
+		return errs.ErrThreadLocked
+	}
+
+	var locked sql.NullBool
+	if err := tx.QueryRowContext(ctx, `
+
+		WITH RECURSIVE ancestors AS (
+
+		SELECT p.id, p.parent_id, 1 AS depth
+		FROM comments c
+		JOIN comments p ON p.id = c.parent_id
+		WHERE c.id = $1
+
+		UNION ALL
+
+		SELECT p.id, p.parent_id, a.depth + 1
+		FROM ancestors a
+		JOIN comments p ON p.id = a.parent_id
+
+		)
+
+		SELECT BOOL_OR(locked_at IS NOT NULL) FROM (
+			SELECT c.locked_at
+			FROM comments c
+			JOIN ancestors a ON a.id = c.id
+			ORDER BY a.depth
+			FOR SHARE OF c
+		) chain`,
+
+		parentID).Scan(&locked); err != nil {
+		return fmt.Errorf("failed to lock ancestors: %w", err)
+	}
+
+	if locked.Bool {
+		return errs.ErrThreadLocked
+	}
+
+	return nil
 
 }
@@ -10,16 +10,24 @@ import (
 
 func (s *Storage) DeleteComment(ctx context.Context, id int64) error {
 
+	query := `
+	
+		DELETE FROM comments 
+		WHERE id = $1`
+
+	if s.config.SoftDelete {
+		query = `
+
+		UPDATE comments
+		SET deleted_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL`
+	}
+
 	row, err := s.db.ExecWithRetry(ctx, retry.Strategy{
 		Attempts: s.config.QueryRetryStrategy.Attempts,
 		Delay:    s.config.QueryRetryStrategy.Delay,
 		Backoff:  s.config.QueryRetryStrategy.Backoff,
-	}, `
-	
-		DELETE FROM comments 
-		WHERE id = $1`,
-
-		id)
+	}, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"Hermes/internal/models"
+	"context"
+	"fmt"
+
+	"github.com/wb-go/wbf/retry"
+)
+
+// GetAncestors walks parent_id up from the given approved comment and returns
+// its ancestors ordered from the thread root down to the direct parent. Like
+// GetCommentTrees, the walk stops at the first comment that is not approved.
+func (s *Storage) GetAncestors(ctx context.Context, id int64) ([]models.Comment, error) {
+
+	rows, err := s.db.QueryWithRetry(ctx, retry.Strategy{
+		Attempts: s.config.QueryRetryStrategy.Attempts,
+		Delay:    s.config.QueryRetryStrategy.Delay,
+		Backoff:  s.config.QueryRetryStrategy.Backoff,
+	}, `
+
+		WITH RECURSIVE ancestors AS (
+
+		SELECT p.*, 1 AS depth
+		FROM comments c
+		JOIN comments p ON p.id = c.parent_id
+		WHERE c.id = $1 AND c.status = 'approved' AND p.status = 'approved'
+
+		UNION ALL
+
+		SELECT p.*, a.depth + 1
+		FROM ancestors a
+		JOIN comments p ON p.id = a.parent_id
+		WHERE p.status = 'approved'
+
+		)
+
+		SELECT `+commentColumns+` FROM ancestors
+		ORDER BY depth DESC
+
+	`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var ancestors []models.Comment
+
+	for rows.Next() {
+
+		ancestor, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		ancestors = append(ancestors, ancestor)
+
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return ancestors, nil
+
+}
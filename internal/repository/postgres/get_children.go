@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"Hermes/internal/models"
+	"context"
+	"fmt"
+
+	"github.com/wb-go/wbf/retry"
+)
+
+// GetChildren returns one page of the approved direct replies to parentID, in reply
+// order for params.Sort, together with the total number of replies.
+func (s *Storage) GetChildren(ctx context.Context, parentID int64, params models.QueryParams) ([]models.Comment, int, error) {
+
+	rows, err := s.db.QueryWithRetry(ctx, retry.Strategy{
+		Attempts: s.config.QueryRetryStrategy.Attempts,
+		Delay:    s.config.QueryRetryStrategy.Delay,
+		Backoff:  s.config.QueryRetryStrategy.Backoff,
+	}, `
+
+		SELECT `+commentColumns+`, COUNT(*) OVER ()
+		FROM comments
+		WHERE parent_id = $1 AND status = 'approved'
+		ORDER BY `+replyOrder(params.Sort)+`
+		LIMIT $2 OFFSET $3`,
+
+		parentID, params.Limit, params.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var children []models.Comment
+	var total int
+
+	for rows.Next() {
+
+		child, err := scanComment(rows, &total)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan row: %w", err)
+		}
+		children = append(children, child)
+
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return children, total, nil
+
+}
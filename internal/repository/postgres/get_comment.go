@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/wb-go/wbf/retry"
+)
+
+func (s *Storage) GetComment(ctx context.Context, id int64) (models.Comment, error) {
+
+	row, err := s.db.QueryRowWithRetry(ctx, retry.Strategy{
+		Attempts: s.config.QueryRetryStrategy.Attempts,
+		Delay:    s.config.QueryRetryStrategy.Delay,
+		Backoff:  s.config.QueryRetryStrategy.Backoff,
+	}, `
+
+		SELECT `+commentColumns+`,
+			(SELECT COUNT(*) FROM comments r WHERE r.parent_id = c.id)
+		FROM comments c
+		WHERE id = $1`,
+
+		id)
+	if err != nil {
+		return models.Comment{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	var childrenCount int
+	comment, err := scanComment(row, &childrenCount)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Comment{}, errs.ErrCommentNotFound
+		}
+		return models.Comment{}, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	comment.ChildrenCount = childrenCount
+
+	return comment, nil
+
+}
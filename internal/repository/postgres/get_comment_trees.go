@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"Hermes/internal/models"
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/wb-go/wbf/retry"
+)
+
+// GetCommentTrees loads the approved subtrees of all given roots in a single round-trip,
+// going no deeper than limits.MaxDepth and taking at most limits.MaxChildren
+// replies per node, picked and ordered by sort. Every node carries its total
+// number of direct replies.
+func (s *Storage) GetCommentTrees(ctx context.Context, rootIDs []int64, sort string, limits models.TreeLimits) ([]models.Comment, error) {
+
+	order := replyOrder(sort)
+
+	rows, err := s.db.QueryWithRetry(ctx, retry.Strategy{
+		Attempts: s.config.QueryRetryStrategy.Attempts,
+		Delay:    s.config.QueryRetryStrategy.Delay,
+		Backoff:  s.config.QueryRetryStrategy.Backoff,
+	}, `
+
+		WITH RECURSIVE tree AS (
+
+		SELECT *, 0 AS depth
+		FROM comments
+		WHERE id = ANY($1) AND status = 'approved'
+
+		UNION ALL
+
+		SELECT c.*, t.depth + 1
+		FROM tree t
+		CROSS JOIN LATERAL (
+			SELECT *
+			FROM comments
+			WHERE parent_id = t.id AND status = 'approved'
+			ORDER BY `+order+`
+			LIMIT NULLIF($3, 0)
+		) c
+		WHERE $2 = 0 OR t.depth < $2
+
+		)
+
+		SELECT `+commentColumns+`,
+			(SELECT COUNT(*) FROM comments r WHERE r.parent_id = tree.id AND r.status = 'approved')
+		FROM tree
+		ORDER BY `+order+`
+
+	`, pq.Array(rootIDs), limits.MaxDepth, limits.MaxChildren)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var comments []models.Comment
+
+	for rows.Next() {
+
+		var childrenCount int
+		comment, err := scanComment(rows, &childrenCount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		comment.ChildrenCount = childrenCount
+		comments = append(comments, comment)
+
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return comments, nil
+
+}
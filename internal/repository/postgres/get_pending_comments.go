@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"Hermes/internal/models"
+	"context"
+	"fmt"
+
+	"github.com/wb-go/wbf/retry"
+)
+
+// GetPendingComments returns one page of the moderation queue across all
+// threads, oldest first, together with the queue length.
+func (s *Storage) GetPendingComments(ctx context.Context, params models.QueryParams) ([]models.Comment, int, error) {
+
+	rows, err := s.db.QueryWithRetry(ctx, retry.Strategy{
+		Attempts: s.config.QueryRetryStrategy.Attempts,
+		Delay:    s.config.QueryRetryStrategy.Delay,
+		Backoff:  s.config.QueryRetryStrategy.Backoff,
+	}, `
+
+		SELECT `+commentColumns+`, COUNT(*) OVER ()
+		FROM comments
+		WHERE status = 'pending'
+		ORDER BY created_at ASC, id ASC
+		LIMIT $1 OFFSET $2`,
+
+		params.Limit, params.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	comments := []models.Comment{}
+	var total int
+
+	for rows.Next() {
+		comment, err := scanComment(rows, &total)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan row: %w", err)
+		}
+		comments = append(comments, comment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return comments, total, nil
+
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/wb-go/wbf/retry"
+)
+
+const reactionCountsQuery = `
+
+	SELECT comment_id, emoji, COUNT(*)
+	FROM comment_reactions
+	WHERE comment_id = ANY($1)
+	GROUP BY comment_id, emoji`
+
+// GetReactions returns the reaction counts of every comment in ids, keyed
+// by comment ID and then by emoji. Comments without reactions are absent.
+func (s *Storage) GetReactions(ctx context.Context, ids []int64) (map[int64]map[string]int, error) {
+
+	if len(ids) == 0 {
+		return map[int64]map[string]int{}, nil
+	}
+
+	rows, err := s.db.QueryWithRetry(ctx, retry.Strategy{
+		Attempts: s.config.QueryRetryStrategy.Attempts,
+		Delay:    s.config.QueryRetryStrategy.Delay,
+		Backoff:  s.config.QueryRetryStrategy.Backoff,
+	}, reactionCountsQuery, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	return scanReactions(rows)
+
+}
+
+func scanReactions(rows *sql.Rows) (map[int64]map[string]int, error) {
+
+	reactions := make(map[int64]map[string]int)
+
+	for rows.Next() {
+
+		var commentID int64
+		var emoji string
+		var count int
+
+		if err := rows.Scan(&commentID, &emoji, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		if reactions[commentID] == nil {
+			reactions[commentID] = make(map[string]int)
+		}
+		reactions[commentID][emoji] = count
+
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return reactions, nil
+
+}
@@ -0,0 +1,77 @@
+package postgres
+
+import (
+	"Hermes/internal/models"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/wb-go/wbf/retry"
+)
+
+// GetReportedComments returns one page of live comments with open reports,
+// most reported first, together with their total number. Reports stop being
+// open once a moderator approves or rejects the comment.
+func (s *Storage) GetReportedComments(ctx context.Context, params models.QueryParams) ([]models.ReportedComment, int, error) {
+
+	rows, err := s.db.QueryWithRetry(ctx, retry.Strategy{
+		Attempts: s.config.QueryRetryStrategy.Attempts,
+		Delay:    s.config.QueryRetryStrategy.Delay,
+		Backoff:  s.config.QueryRetryStrategy.Backoff,
+	}, `
+
+		WITH reasons AS (
+		    SELECT comment_id, reason, COUNT(*) AS reports, MAX(created_at) AS last_reported_at
+		    FROM comment_reports
+		    WHERE reviewed_at IS NULL
+		    GROUP BY comment_id, reason
+		), reported AS (
+		    SELECT comment_id,
+		           SUM(reports)::integer AS reports,
+		           jsonb_object_agg(reason, reports) AS reasons,
+		           MAX(last_reported_at) AS last_reported_at
+		    FROM reasons
+		    GROUP BY comment_id
+		)
+		SELECT `+commentColumns+`, r.reports, r.reasons, r.last_reported_at, COUNT(*) OVER ()
+		FROM comments
+		JOIN reported r ON r.comment_id = comments.id
+		WHERE deleted_at IS NULL AND status IN ('approved', 'hidden')
+		ORDER BY r.reports DESC, r.last_reported_at DESC, id DESC
+		LIMIT $1 OFFSET $2`,
+
+		params.Limit, params.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	comments := []models.ReportedComment{}
+	var total int
+
+	for rows.Next() {
+
+		var reported models.ReportedComment
+		var reasons []byte
+
+		comment, err := scanComment(rows, &reported.Reports, &reasons, &reported.LastReportedAt, &total)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		if err := json.Unmarshal(reasons, &reported.Reasons); err != nil {
+			return nil, 0, fmt.Errorf("failed to decode reasons: %w", err)
+		}
+
+		reported.Comment = comment
+		comments = append(comments, reported)
+
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return comments, total, nil
+
+}
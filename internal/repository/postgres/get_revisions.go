@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wb-go/wbf/retry"
+)
+
+// GetRevisions returns the edit history of an approved comment, oldest first.
+// Deleted comments are reported as not found so that their earlier versions
+// stay hidden behind the "[deleted]" tombstone.
+func (s *Storage) GetRevisions(ctx context.Context, commentID int64) ([]models.Revision, error) {
+
+	rows, err := s.db.QueryWithRetry(ctx, retry.Strategy{
+		Attempts: s.config.QueryRetryStrategy.Attempts,
+		Delay:    s.config.QueryRetryStrategy.Delay,
+		Backoff:  s.config.QueryRetryStrategy.Backoff,
+	}, `
+
+		SELECT r.id, r.content, r.author, r.created_at
+		FROM comments c
+		LEFT JOIN comment_revisions r ON r.comment_id = c.id
+		WHERE c.id = $1 AND c.status = 'approved' AND c.deleted_at IS NULL
+		ORDER BY r.id ASC`,
+		commentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	found := false
+	revisions := []models.Revision{}
+
+	for rows.Next() {
+
+		found = true
+
+		var id *int64
+		var content, author *string
+		var createdAt *time.Time
+
+		if err := rows.Scan(&id, &content, &author, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+
+		if id == nil { // comment exists but was never edited
+			continue
+		}
+
+		revisions = append(revisions, models.Revision{
+			ID:        *id,
+			CommentID: commentID,
+			Content:   *content,
+			Author:    *author,
+			CreatedAt: *createdAt,
+		})
+
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	if !found {
+		return nil, errs.ErrCommentNotFound
+	}
+
+	return revisions, nil
+
+}
@@ -11,15 +11,14 @@ import (
 
 func (s *Storage) GetRootComments(ctx context.Context, params models.QueryParams) ([]models.Comment, error) {
 
-	order := "created_at DESC"
-	if params.Sort == "created_at_asc" {
-		order = "created_at ASC"
-	}
+	order, keyset := rootOrder(params.Sort)
 
 	var rows *sql.Rows
 	var err error
 
-	if params.ParentID == nil {
+	switch {
+
+	case params.ParentID != nil:
 
 		rows, err = s.db.QueryWithRetry(ctx, retry.Strategy{
 			Attempts: s.config.QueryRetryStrategy.Attempts,
@@ -27,25 +26,46 @@ func (s *Storage) GetRootComments(ctx context.Context, params models.QueryParams
 			Backoff:  s.config.QueryRetryStrategy.Backoff,
 		}, `
 
-            SELECT * FROM comments
-            WHERE parent_id IS NULL
-            ORDER BY `+order+`
-            LIMIT $1 OFFSET $2`,
+            SELECT `+commentColumns+` FROM comments
+            WHERE id = $1 AND thread_key = $2 AND status = 'approved'`,
 
-			params.Limit, params.Offset)
+			params.ParentID, params.ThreadKey)
 
-	} else {
+	case params.Cursor != nil && keyset != "" && params.Cursor.PinnedAt == nil:
 
+		// A cursor among the unpinned comments never leads back to the pinned ones.
 		rows, err = s.db.QueryWithRetry(ctx, retry.Strategy{
 			Attempts: s.config.QueryRetryStrategy.Attempts,
 			Delay:    s.config.QueryRetryStrategy.Delay,
 			Backoff:  s.config.QueryRetryStrategy.Backoff,
 		}, `
 
-            SELECT * FROM comments
-            WHERE id = $1`,
+            SELECT `+commentColumns+` FROM comments
+            WHERE parent_id IS NULL AND thread_key = $2 AND status = 'approved'
+                AND pinned_at IS NULL AND `+cursorPosition(keyset)+`
+            ORDER BY `+order+`
+            LIMIT $1`,
+
+			params.Limit, params.ThreadKey, params.Cursor.CreatedAt, params.Cursor.ID)
+
+	case params.Cursor != nil && keyset != "":
+
+		rows, err = s.db.QueryWithRetry(ctx, retry.Strategy{
+			Attempts: s.config.QueryRetryStrategy.Attempts,
+			Delay:    s.config.QueryRetryStrategy.Delay,
+			Backoff:  s.config.QueryRetryStrategy.Backoff,
+		}, pinnedFirst(order, " AND (pinned_at < $5 OR (pinned_at = $5 AND "+cursorPosition(keyset)+"))", "0"),
+			params.Limit, params.ThreadKey, params.Cursor.CreatedAt, params.Cursor.ID, *params.Cursor.PinnedAt)
+
+	default:
+
+		rows, err = s.db.QueryWithRetry(ctx, retry.Strategy{
+			Attempts: s.config.QueryRetryStrategy.Attempts,
+			Delay:    s.config.QueryRetryStrategy.Delay,
+			Backoff:  s.config.QueryRetryStrategy.Backoff,
+		}, pinnedFirst(order, "", "$3::integer"),
+			params.Limit, params.ThreadKey, params.Offset)
 
-			params.ParentID)
 	}
 
 	if err != nil {
@@ -57,21 +77,18 @@ func (s *Storage) GetRootComments(ctx context.Context, params models.QueryParams
 
 	for rows.Next() {
 
-		var c models.Comment
-		if err := rows.Scan(
-			&c.ID,
-			&c.ParentID,
-			&c.Content,
-			&c.Author,
-			&c.CreatedAt,
-			&c.UpdatedAt,
-		); err != nil {
+		c, err := scanComment(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
 		comments = append(comments, c)
 
 	}
 
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
 	return comments, nil
 
 }
@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"Hermes/internal/errs"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/wb-go/wbf/retry"
+)
+
+func (s *Storage) GetUserRole(ctx context.Context, subject string) (string, error) {
+
+	row, err := s.db.QueryRowWithRetry(ctx, retry.Strategy{
+		Attempts: s.config.QueryRetryStrategy.Attempts,
+		Delay:    s.config.QueryRetryStrategy.Delay,
+		Backoff:  s.config.QueryRetryStrategy.Backoff,
+	}, `
+
+		SELECT role FROM users
+		WHERE subject = $1`,
+
+		subject)
+	if err != nil {
+		return "", fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	var role string
+	if err := row.Scan(&role); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", errs.ErrUserNotFound
+		}
+		return "", fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	return role, nil
+
+}
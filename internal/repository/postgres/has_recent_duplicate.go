@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wb-go/wbf/retry"
+)
+
+// HasRecentDuplicate reports whether author posted a live comment with the
+// same content, ignoring surrounding spaces, tabs and line breaks, within
+// window. content is expected to be trimmed of the same characters.
+func (s *Storage) HasRecentDuplicate(ctx context.Context, author, content string, window time.Duration) (bool, error) {
+
+	row, err := s.db.QueryRowWithRetry(ctx, retry.Strategy{
+		Attempts: s.config.QueryRetryStrategy.Attempts,
+		Delay:    s.config.QueryRetryStrategy.Delay,
+		Backoff:  s.config.QueryRetryStrategy.Backoff,
+	}, `
+
+		SELECT EXISTS (
+		    SELECT 1 FROM comments
+		    WHERE author = $1
+		      AND BTRIM(content, E' \t\n\r\f\v') = $2
+		      AND created_at > NOW() - MAKE_INTERVAL(secs => $3)
+		      AND deleted_at IS NULL
+		)`,
+
+		author, content, window.Seconds())
+	if err != nil {
+		return false, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	var exists bool
+	if err := row.Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	return exists, nil
+
+}
@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/wb-go/wbf/retry"
+)
+
+// LockComment closes the subtree under a public comment, normally a thread
+// root, to new replies. Locking it again keeps the original time.
+func (s *Storage) LockComment(ctx context.Context, id int64) (models.Comment, error) {
+	return s.setLocked(ctx, id, "COALESCE(locked_at, NOW())")
+}
+
+// UnlockComment reopens the subtree under a public comment to replies.
+func (s *Storage) UnlockComment(ctx context.Context, id int64) (models.Comment, error) {
+	return s.setLocked(ctx, id, "NULL")
+}
+
+func (s *Storage) setLocked(ctx context.Context, id int64, lockedAt string) (models.Comment, error) {
+
+	row, err := s.db.QueryRowWithRetry(ctx, retry.Strategy{
+		Attempts: s.config.QueryRetryStrategy.Attempts,
+		Delay:    s.config.QueryRetryStrategy.Delay,
+		Backoff:  s.config.QueryRetryStrategy.Backoff,
+	}, `
+
+		UPDATE comments
+		SET locked_at = `+lockedAt+`
+		WHERE id = $1 AND deleted_at IS NULL AND status = 'approved'
+		RETURNING `+commentColumns,
+
+		id)
+	if err != nil {
+		return models.Comment{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	comment, err := scanComment(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Comment{}, errs.ErrCommentNotFound
+		}
+		return models.Comment{}, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	return comment, nil
+
+}
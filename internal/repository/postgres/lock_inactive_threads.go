@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wb-go/wbf/retry"
+)
+
+// LockInactiveThreads locks every open thread whose newest post or edit,
+// anywhere in the thread, is older than inactivity. It returns the number
+// of threads locked.
+func (s *Storage) LockInactiveThreads(ctx context.Context, inactivity time.Duration) (int, error) {
+
+	result, err := s.db.ExecWithRetry(ctx, retry.Strategy{
+		Attempts: s.config.QueryRetryStrategy.Attempts,
+		Delay:    s.config.QueryRetryStrategy.Delay,
+		Backoff:  s.config.QueryRetryStrategy.Backoff,
+	}, `
+
+		WITH RECURSIVE tree AS (
+
+		SELECT id AS root_id, id, GREATEST(created_at, updated_at) AS active_at
+		FROM comments
+		WHERE parent_id IS NULL AND locked_at IS NULL AND deleted_at IS NULL
+
+		UNION ALL
+
+		SELECT t.root_id, c.id, GREATEST(c.created_at, c.updated_at)
+		FROM comments c
+		JOIN tree t ON c.parent_id = t.id
+
+		)
+
+		UPDATE comments
+		SET locked_at = NOW()
+		WHERE id IN (
+		    SELECT root_id FROM tree
+		    GROUP BY root_id
+		    HAVING MAX(active_at) < NOW() - MAKE_INTERVAL(secs => $1)
+		)`,
+
+		inactivity.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get number of affected rows: %w", err)
+	}
+
+	return int(rows), nil
+
+}
@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/wb-go/wbf/retry"
+)
+
+// ModerateComment moves a comment awaiting a decision to status and marks its
+// open reports as reviewed. That is a pending or hidden comment, or an
+// approved one with open reports; approving the latter dismisses the
+// reports. Comments that are awaiting no decision are reported as
+// ErrCommentNotFound.
+func (s *Storage) ModerateComment(ctx context.Context, id int64, status string) (models.Comment, error) {
+
+	row, err := s.db.QueryRowWithRetry(ctx, retry.Strategy{
+		Attempts: s.config.QueryRetryStrategy.Attempts,
+		Delay:    s.config.QueryRetryStrategy.Delay,
+		Backoff:  s.config.QueryRetryStrategy.Backoff,
+	}, `
+
+		WITH moderated AS (
+		    UPDATE comments
+		    SET status = $2
+		    WHERE id = $1 AND (status IN ('pending', 'hidden') OR (status = 'approved' AND EXISTS (
+		        SELECT 1 FROM comment_reports WHERE comment_id = $1 AND reviewed_at IS NULL
+		    )))
+		    RETURNING *
+		), reviewed AS (
+		    UPDATE comment_reports
+		    SET reviewed_at = NOW()
+		    WHERE comment_id IN (SELECT id FROM moderated) AND reviewed_at IS NULL
+		)
+		SELECT `+commentColumns+` FROM moderated`,
+
+		id, status)
+	if err != nil {
+		return models.Comment{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	comment, err := scanComment(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Comment{}, errs.ErrCommentNotFound
+		}
+		return models.Comment{}, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	return comment, nil
+
+}
@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// MoveComment reparents a comment together with its replies. A nil parentID
+// promotes the comment to a thread root; otherwise the new parent must be a
+// public comment outside the moved subtree. The subtree takes on the new
+// parent's thread key and the old and new parents' reply counts follow.
+//
+// Rows on the new parent's ancestry are locked before the subtree is
+// checked, so two concurrent moves can not close a cycle between them.
+func (s *Storage) MoveComment(ctx context.Context, id int64, parentID *int64) (models.Comment, error) {
+
+	var comment models.Comment
+
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+
+		var (
+			oldParentID sql.NullInt64
+			threadKey   string
+		)
+		if err := tx.QueryRowContext(ctx, `
+
+			SELECT parent_id, thread_key FROM comments
+			WHERE id = $1 AND deleted_at IS NULL
+			FOR UPDATE`,
+
+			id).Scan(&oldParentID, &threadKey); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errs.ErrCommentNotFound
+			}
+			return fmt.Errorf("failed to lock comment: %w", err)
+		}
+
+		if parentID != nil {
+
+			if err := tx.QueryRowContext(ctx, `
+
+				SELECT thread_key FROM comments
+				WHERE id = $1 AND deleted_at IS NULL AND status = 'approved'
+				FOR UPDATE`,
+
+				*parentID).Scan(&threadKey); err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return errs.ErrParentNotFound
+				}
+				return fmt.Errorf("failed to lock parent: %w", err)
+			}
+
+			if _, err := tx.ExecContext(ctx, `
+
+				WITH RECURSIVE ancestors AS (
+
+				SELECT id, parent_id FROM comments WHERE id = $1
+
+				UNION ALL
+
+				SELECT p.id, p.parent_id
+				FROM comments p
+				JOIN ancestors a ON p.id = a.parent_id
+
+				)
+
+				SELECT id FROM comments
+				WHERE id IN (SELECT id FROM ancestors)
+				ORDER BY id
+				FOR UPDATE`,
+
+				*parentID); err != nil {
+				return fmt.Errorf("failed to lock ancestors: %w", err)
+			}
+
+			var cycle bool
+			if err := tx.QueryRowContext(ctx, `
+
+				WITH RECURSIVE tree AS (
+
+				SELECT id FROM comments WHERE id = $1
+
+				UNION ALL
+
+				SELECT c.id
+				FROM comments c
+				JOIN tree t ON c.parent_id = t.id
+
+				)
+
+				SELECT EXISTS (SELECT 1 FROM tree WHERE id = $2)`,
+
+				id, *parentID).Scan(&cycle); err != nil {
+				return fmt.Errorf("failed to check subtree: %w", err)
+			}
+
+			if cycle {
+				return errs.ErrInvalidMove
+			}
+
+		}
+
+		if oldParentID.Valid != (parentID != nil) || (parentID != nil && oldParentID.Int64 != *parentID) {
+
+			if _, err := tx.ExecContext(ctx, `
+
+				UPDATE comments
+				SET reply_count = reply_count + CASE WHEN id = $2 THEN 1 ELSE -1 END
+				WHERE id = $1 OR id = $2`,
+
+				oldParentID, parentID); err != nil {
+				return fmt.Errorf("failed to update reply counts: %w", err)
+			}
+
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+
+			WITH RECURSIVE tree AS (
+
+			SELECT id FROM comments WHERE id = $1
+
+			UNION ALL
+
+			SELECT c.id
+			FROM comments c
+			JOIN tree t ON c.parent_id = t.id
+
+			)
+
+			UPDATE comments
+			SET thread_key = $2
+			WHERE id IN (SELECT id FROM tree) AND thread_key <> $2`,
+
+			id, threadKey); err != nil {
+			return fmt.Errorf("failed to update thread key: %w", err)
+		}
+
+		// Only root comments stay pinned.
+		moved, err := scanComment(tx.QueryRowContext(ctx, `
+
+			UPDATE comments
+			SET parent_id = $2::integer,
+			    pinned_at = CASE WHEN $2::integer IS NULL THEN pinned_at END
+			WHERE id = $1
+			RETURNING `+commentColumns,
+
+			id, parentID))
+		if err != nil {
+			return fmt.Errorf("failed to move comment: %w", err)
+		}
+
+		comment = moved
+		return nil
+
+	})
+	if err != nil {
+		return models.Comment{}, err
+	}
+
+	return comment, nil
+
+}
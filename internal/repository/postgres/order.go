@@ -0,0 +1,83 @@
+package postgres
+
+const (
+	sortCreatedAtAsc  = "created_at_asc"
+	sortTop           = "top"
+	sortControversial = "controversial"
+	sortHot           = "hot"
+)
+
+// controversy ranks comments with many votes split evenly between up and down
+// above one-sided ones: total votes raised to the power of their balance.
+const controversy = `CASE WHEN upvotes = 0 OR downvotes = 0 THEN 0
+	ELSE POWER(upvotes + downvotes, LEAST(upvotes, downvotes)::float / GREATEST(upvotes, downvotes)) END`
+
+// rootOrder returns the ORDER BY clause for root comments and, for the
+// time-based sorts, the comparison operator used for keyset pagination.
+// keyset is empty for sorts that cannot be paged with a cursor. The order
+// applies within the pinned and the unpinned roots; see pinnedFirst.
+func rootOrder(sort string) (order string, keyset string) {
+	switch sort {
+	case sortCreatedAtAsc:
+		return "created_at ASC, id ASC", ">"
+	case sortTop:
+		return "upvotes - downvotes DESC, created_at DESC, id DESC", ""
+	case sortControversial:
+		return controversy + " DESC, created_at DESC, id DESC", ""
+	case sortHot:
+		return "hot_score DESC, id DESC", ""
+	default:
+		return "created_at DESC, id DESC", "<"
+	}
+}
+
+// pinnedFirst returns a query for root comments of thread $2 in order,
+// pinned ones first, most recently pinned first, skipping offset and
+// returning at most $1. Pinned and unpinned roots are read separately, each
+// cut to size in an order the root indexes serve, so only those few rows
+// are merged rather than every root in the thread being sorted. pinnedWhere
+// further narrows the pinned roots.
+func pinnedFirst(order, pinnedWhere, offset string) string {
+	return `
+
+		SELECT ` + commentColumns + ` FROM (
+
+			(SELECT * FROM comments
+			WHERE parent_id IS NULL AND thread_key = $2 AND status = 'approved'
+				AND pinned_at IS NOT NULL` + pinnedWhere + `
+			ORDER BY pinned_at DESC, ` + order + `
+			LIMIT $1::integer + ` + offset + `)
+
+			UNION ALL
+
+			(SELECT * FROM comments
+			WHERE parent_id IS NULL AND thread_key = $2 AND status = 'approved'
+				AND pinned_at IS NULL
+			ORDER BY ` + order + `
+			LIMIT $1::integer + ` + offset + `)
+
+		) comments
+		ORDER BY pinned_at DESC NULLS LAST, ` + order + `
+		LIMIT $1 OFFSET ` + offset
+}
+
+// cursorPosition returns the condition selecting comments past a cursor in
+// rootOrder, given the cursor's created_at and id in $3 and $4.
+func cursorPosition(keyset string) string {
+	return "(created_at, id) " + keyset + " ($3, $4)"
+}
+
+// replyOrder returns the ORDER BY clause for replies inside a tree. Replies
+// read oldest first unless the reader asked for the best ones.
+func replyOrder(sort string) string {
+	switch sort {
+	case sortTop:
+		return "upvotes - downvotes DESC, created_at ASC, id ASC"
+	case sortControversial:
+		return controversy + " DESC, created_at ASC, id ASC"
+	case sortHot:
+		return "hot_score DESC, created_at ASC, id ASC"
+	default:
+		return "created_at ASC, id ASC"
+	}
+}
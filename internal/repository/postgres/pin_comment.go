@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/wb-go/wbf/retry"
+)
+
+// PinComment pins a public root comment to the top of its thread's listing.
+// Pinning it again keeps its place among the other pinned comments.
+func (s *Storage) PinComment(ctx context.Context, id int64) (models.Comment, error) {
+	return s.setPinned(ctx, id, "COALESCE(pinned_at, NOW())")
+}
+
+// UnpinComment returns a public root comment to its place in the listing.
+func (s *Storage) UnpinComment(ctx context.Context, id int64) (models.Comment, error) {
+	return s.setPinned(ctx, id, "NULL")
+}
+
+func (s *Storage) setPinned(ctx context.Context, id int64, pinnedAt string) (models.Comment, error) {
+
+	row, err := s.db.QueryRowWithRetry(ctx, retry.Strategy{
+		Attempts: s.config.QueryRetryStrategy.Attempts,
+		Delay:    s.config.QueryRetryStrategy.Delay,
+		Backoff:  s.config.QueryRetryStrategy.Backoff,
+	}, `
+
+		UPDATE comments
+		SET pinned_at = `+pinnedAt+`
+		WHERE id = $1 AND parent_id IS NULL AND deleted_at IS NULL AND status = 'approved'
+		RETURNING `+commentColumns,
+
+		id)
+	if err != nil {
+		return models.Comment{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	comment, err := scanComment(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Comment{}, errs.ErrCommentNotFound
+		}
+		return models.Comment{}, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	return comment, nil
+
+}
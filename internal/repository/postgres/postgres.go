@@ -3,8 +3,12 @@ package postgres
 import (
 	"Hermes/internal/config"
 	"Hermes/internal/logger"
+	"context"
+	"database/sql"
+	"fmt"
 
 	"github.com/wb-go/wbf/dbpg"
+	"github.com/wb-go/wbf/retry"
 )
 
 type Storage struct {
@@ -32,3 +36,29 @@ func (s *Storage) DB() *dbpg.DB {
 func (s *Storage) Config() *config.Storage {
 	return &s.config
 }
+
+// withTx runs fn inside a transaction. Only BEGIN is retried, so errors
+// returned by fn (such as errs.ErrCommentNotFound) reach the caller at once.
+func (s *Storage) withTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+
+	tx, err := s.db.BeginTxWithRetry(ctx, retry.Strategy{
+		Attempts: s.config.QueryRetryStrategy.Attempts,
+		Delay:    s.config.QueryRetryStrategy.Delay,
+		Backoff:  s.config.QueryRetryStrategy.Backoff,
+	}, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+
+}
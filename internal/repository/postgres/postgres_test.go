@@ -10,10 +10,10 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
-	"github.com/lib/pq"
 	wbf "github.com/wb-go/wbf/config"
 	"github.com/wb-go/wbf/dbpg"
 	"github.com/wb-go/wbf/retry"
@@ -64,7 +64,7 @@ func setupTest(t *testing.T) {
 	_, err := testStorage.DB().ExecWithRetry(ctx, retry.Strategy{Attempts: 3, Delay: 100 * time.Millisecond, Backoff: 1.5}, `
 	
 	TRUNCATE TABLE comments 
-	RESTART IDENTITY`)
+	RESTART IDENTITY CASCADE`)
 
 	if err != nil {
 		t.Fatalf("failed to truncate comments: %v", err)
@@ -79,15 +79,8 @@ func TestCreateComment_Errors(t *testing.T) {
 	comment := models.Comment{ParentID: ptr(int64(999999999)), Content: "Invalid parent", Author: "test"}
 
 	_, err := testStorage.CreateComment(context.Background(), comment)
-	if err == nil {
-		t.Fatalf("expected error for invalid parent, got nil")
-	}
-
-	var pqErr *pq.Error
-	if errors.As(err, &pqErr) && string(pqErr.Code) == "23503" {
-		t.Logf("expected foreign key error captured: %v", err)
-	} else {
-		t.Fatalf("unexpected error: %v", err)
+	if !errors.Is(err, errs.ErrParentNotFound) {
+		t.Fatalf("expected ErrParentNotFound for invalid parent, got %v", err)
 	}
 
 }
@@ -130,7 +123,187 @@ func TestDeleteComment(t *testing.T) {
 
 }
 
-func TestGetCommentTree(t *testing.T) {
+func TestUpdateComment(t *testing.T) {
+
+	setupTest(t)
+	ctx := context.Background()
+
+	id, err := testStorage.CreateComment(ctx, models.Comment{Content: "Tpyo", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+
+	updated, err := testStorage.UpdateComment(ctx, models.Comment{ID: id, Content: "Typo", Author: "test"})
+	if err != nil {
+		t.Fatalf("UpdateComment failed: %v", err)
+	}
+
+	if updated.ID != id || updated.Content != "Typo" {
+		t.Fatalf("unexpected updated comment: %+v", updated)
+	}
+
+	if !updated.UpdatedAt.After(updated.CreatedAt) {
+		t.Fatalf("expected updated_at after created_at, got %v <= %v", updated.UpdatedAt, updated.CreatedAt)
+	}
+
+	_, err = testStorage.UpdateComment(ctx, models.Comment{ID: 999999, Content: "Nope", Author: "test"})
+	if err != errs.ErrCommentNotFound {
+		t.Fatalf("expected ErrCommentNotFound, got %v", err)
+	}
+
+}
+
+func TestGetRevisions(t *testing.T) {
+
+	setupTest(t)
+	ctx := context.Background()
+
+	id, err := testStorage.CreateComment(ctx, models.Comment{Content: "First", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	revisions, err := testStorage.GetRevisions(ctx, id)
+	if err != nil {
+		t.Fatalf("GetRevisions failed: %v", err)
+	}
+
+	if len(revisions) != 0 {
+		t.Fatalf("expected 0 revisions for unedited comment, got %d", len(revisions))
+	}
+
+	for _, content := range []string{"Second", "Third"} {
+		if _, err := testStorage.UpdateComment(ctx, models.Comment{ID: id, Content: content, Author: "test"}); err != nil {
+			t.Fatalf("UpdateComment failed: %v", err)
+		}
+	}
+
+	revisions, err = testStorage.GetRevisions(ctx, id)
+	if err != nil {
+		t.Fatalf("GetRevisions failed: %v", err)
+	}
+
+	if len(revisions) != 2 || revisions[0].Content != "First" || revisions[1].Content != "Second" {
+		t.Fatalf("unexpected revisions: %+v", revisions)
+	}
+
+	_, err = testStorage.GetRevisions(ctx, 999999)
+	if err != errs.ErrCommentNotFound {
+		t.Fatalf("expected ErrCommentNotFound, got %v", err)
+	}
+
+}
+
+func TestDeleteComment_Soft(t *testing.T) {
+
+	setupTest(t)
+	ctx := context.Background()
+
+	log, _ := logger.NewLogger(config.Logger{Debug: true})
+	cfg := *testStorage.Config()
+	cfg.SoftDelete = true
+	softStorage := postgres.NewStorage(log, cfg, testStorage.DB())
+
+	rootID, err := softStorage.CreateComment(ctx, models.Comment{Content: "Root", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	childID, err := softStorage.CreateComment(ctx, models.Comment{ParentID: &rootID, Content: "Child", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	if _, err := softStorage.UpdateComment(ctx, models.Comment{ID: rootID, Content: "Root, edited", Author: "test"}); err != nil {
+		t.Fatalf("UpdateComment failed: %v", err)
+	}
+
+	if err := softStorage.DeleteComment(ctx, rootID); err != nil {
+		t.Fatalf("DeleteComment failed: %v", err)
+	}
+
+	if _, err := softStorage.GetRevisions(ctx, rootID); err != errs.ErrCommentNotFound {
+		t.Fatalf("expected ErrCommentNotFound for revisions of a tombstone, got %v", err)
+	}
+
+	tree, err := softStorage.GetCommentTrees(ctx, []int64{rootID}, "", models.TreeLimits{})
+	if err != nil {
+		t.Fatalf("GetCommentTrees failed: %v", err)
+	}
+
+	if len(tree) != 2 || tree[0].DeletedAt == nil || tree[1].ID != childID || tree[1].DeletedAt != nil {
+		t.Fatalf("expected tombstone root with live child, got %+v", tree)
+	}
+
+	err = softStorage.DeleteComment(ctx, rootID)
+	if err != errs.ErrCommentNotFound {
+		t.Fatalf("expected ErrCommentNotFound on second delete, got %v", err)
+	}
+
+	_, err = softStorage.UpdateComment(ctx, models.Comment{ID: rootID, Content: "Back", Author: "test"})
+	if err != errs.ErrCommentNotFound {
+		t.Fatalf("expected ErrCommentNotFound when editing a tombstone, got %v", err)
+	}
+
+}
+
+func TestGetCommentAndAncestors(t *testing.T) {
+
+	setupTest(t)
+	ctx := context.Background()
+
+	rootID, err := testStorage.CreateComment(ctx, models.Comment{Content: "Root", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	childID, err := testStorage.CreateComment(ctx, models.Comment{ParentID: &rootID, Content: "Child", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	grandchildID, err := testStorage.CreateComment(ctx, models.Comment{ParentID: &childID, Content: "Grandchild", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	comment, err := testStorage.GetComment(ctx, childID)
+	if err != nil {
+		t.Fatalf("GetComment failed: %v", err)
+	}
+
+	if comment.ID != childID || comment.Content != "Child" || comment.ChildrenCount != 1 {
+		t.Fatalf("unexpected comment: %+v", comment)
+	}
+
+	_, err = testStorage.GetComment(ctx, 999999)
+	if err != errs.ErrCommentNotFound {
+		t.Fatalf("expected ErrCommentNotFound, got %v", err)
+	}
+
+	ancestors, err := testStorage.GetAncestors(ctx, grandchildID)
+	if err != nil {
+		t.Fatalf("GetAncestors failed: %v", err)
+	}
+
+	if len(ancestors) != 2 || ancestors[0].ID != rootID || ancestors[1].ID != childID {
+		t.Fatalf("unexpected ancestors: %+v", ancestors)
+	}
+
+	ancestors, err = testStorage.GetAncestors(ctx, rootID)
+	if err != nil {
+		t.Fatalf("GetAncestors failed: %v", err)
+	}
+
+	if len(ancestors) != 0 {
+		t.Fatalf("expected no ancestors for a root, got %+v", ancestors)
+	}
+
+}
+
+func TestGetCommentTrees_SingleRoot(t *testing.T) {
 
 	setupTest(t)
 
@@ -158,9 +331,9 @@ func TestGetCommentTree(t *testing.T) {
 		t.Fatalf("CreateComment failed: %v", err)
 	}
 
-	tree, err := testStorage.GetCommentTree(ctx, rootID)
+	tree, err := testStorage.GetCommentTrees(ctx, []int64{rootID}, "", models.TreeLimits{})
 	if err != nil {
-		t.Fatalf("GetCommentTree failed: %v", err)
+		t.Fatalf("GetCommentTrees failed: %v", err)
 	}
 
 	if len(tree) != 3 {
@@ -171,9 +344,9 @@ func TestGetCommentTree(t *testing.T) {
 		t.Fatalf("unexpected order or IDs: %+v", tree)
 	}
 
-	tree, err = testStorage.GetCommentTree(ctx, 999999)
+	tree, err = testStorage.GetCommentTrees(ctx, []int64{999999}, "", models.TreeLimits{})
 	if err != nil {
-		t.Fatalf("GetCommentTree failed: %v", err)
+		t.Fatalf("GetCommentTrees failed: %v", err)
 	}
 
 	if len(tree) != 0 {
@@ -182,6 +355,94 @@ func TestGetCommentTree(t *testing.T) {
 
 }
 
+func TestGetCommentTrees(t *testing.T) {
+
+	setupTest(t)
+	ctx := context.Background()
+
+	root1ID, err := testStorage.CreateComment(ctx, models.Comment{Content: "Root1", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	root2ID, err := testStorage.CreateComment(ctx, models.Comment{Content: "Root2", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	root3ID, err := testStorage.CreateComment(ctx, models.Comment{Content: "Root3", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	for _, parentID := range []int64{root1ID, root2ID, root3ID} {
+		if _, err := testStorage.CreateComment(ctx, models.Comment{ParentID: &parentID, Content: "Child", Author: "test"}); err != nil {
+			t.Fatalf("CreateComment failed: %v", err)
+		}
+	}
+
+	flat, err := testStorage.GetCommentTrees(ctx, []int64{root1ID, root3ID}, "", models.TreeLimits{})
+	if err != nil {
+		t.Fatalf("GetCommentTrees failed: %v", err)
+	}
+
+	if len(flat) != 4 {
+		t.Fatalf("expected 4 comments, got %d", len(flat))
+	}
+
+	for _, c := range flat {
+		if c.ID == root2ID || (c.ParentID != nil && *c.ParentID == root2ID) {
+			t.Fatalf("unexpected comment from another tree: %+v", c)
+		}
+	}
+
+}
+
+func TestGetCommentTrees_Limits(t *testing.T) {
+
+	setupTest(t)
+	ctx := context.Background()
+
+	rootID, err := testStorage.CreateComment(ctx, models.Comment{Content: "Root", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	var firstChildID int64
+	for i := 0; i < 3; i++ {
+		id, err := testStorage.CreateComment(ctx, models.Comment{ParentID: &rootID, Content: "Child", Author: "test"})
+		if err != nil {
+			t.Fatalf("CreateComment failed: %v", err)
+		}
+		if i == 0 {
+			firstChildID = id
+		}
+	}
+
+	if _, err := testStorage.CreateComment(ctx, models.Comment{ParentID: &firstChildID, Content: "Grandchild", Author: "test"}); err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	flat, err := testStorage.GetCommentTrees(ctx, []int64{rootID}, "", models.TreeLimits{MaxDepth: 1, MaxChildren: 2})
+	if err != nil {
+		t.Fatalf("GetCommentTrees failed: %v", err)
+	}
+
+	if len(flat) != 3 || flat[0].ID != rootID || flat[0].ChildrenCount != 3 || flat[1].ID != firstChildID || flat[1].ChildrenCount != 1 {
+		t.Fatalf("unexpected limited tree: %+v", flat)
+	}
+
+	children, total, err := testStorage.GetChildren(ctx, rootID, models.QueryParams{Limit: 2, Offset: 2})
+	if err != nil {
+		t.Fatalf("GetChildren failed: %v", err)
+	}
+
+	if total != 3 || len(children) != 1 {
+		t.Fatalf("unexpected children page: total=%d %+v", total, children)
+	}
+
+}
+
 func TestGetRootComments(t *testing.T) {
 
 	setupTest(t)
@@ -239,6 +500,15 @@ func TestGetRootComments(t *testing.T) {
 		t.Fatalf("unexpected roots (asc): %+v", roots)
 	}
 
+	total, err := testStorage.CountRootComments(ctx, params)
+	if err != nil {
+		t.Fatalf("CountRootComments failed: %v", err)
+	}
+
+	if total != 3 {
+		t.Fatalf("expected 3 root comments, got %d", total)
+	}
+
 	parentID := root2ID
 	params.ParentID = &parentID
 	roots, err = testStorage.GetRootComments(ctx, params)
@@ -275,6 +545,989 @@ func TestGetRootComments(t *testing.T) {
 
 }
 
+func TestGetRootComments_Cursor(t *testing.T) {
+
+	setupTest(t)
+	ctx := context.Background()
+
+	var ids []int64
+	for i := 0; i < 5; i++ {
+		id, err := testStorage.CreateComment(ctx, models.Comment{Content: fmt.Sprintf("Root%d", i), Author: "test"})
+		if err != nil {
+			t.Fatalf("CreateComment failed: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	for _, sort := range []string{"created_at_desc", "created_at_asc"} {
+
+		params := models.QueryParams{Limit: 2, Sort: sort}
+		var seen []int64
+
+		for {
+			roots, err := testStorage.GetRootComments(ctx, params)
+			if err != nil {
+				t.Fatalf("GetRootComments failed: %v", err)
+			}
+			for _, r := range roots {
+				seen = append(seen, r.ID)
+			}
+			if len(roots) < params.Limit {
+				break
+			}
+			last := roots[len(roots)-1]
+			params.Cursor = &models.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+		}
+
+		if len(seen) != len(ids) {
+			t.Fatalf("%s: expected %d roots across pages, got %v", sort, len(ids), seen)
+		}
+
+		for i := range seen {
+			want := ids[len(ids)-1-i]
+			if sort == "created_at_asc" {
+				want = ids[i]
+			}
+			if seen[i] != want {
+				t.Fatalf("%s: unexpected order %v", sort, seen)
+			}
+		}
+
+	}
+
+}
+
+func TestSearchComments(t *testing.T) {
+
+	setupTest(t)
+	ctx := context.Background()
+
+	rootID, err := testStorage.CreateComment(ctx, models.Comment{Content: "Talking about pagination", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	childID, err := testStorage.CreateComment(ctx, models.Comment{ParentID: &rootID, Content: "Unrelated reply", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	matchID, err := testStorage.CreateComment(ctx, models.Comment{ParentID: &childID, Content: "Keyset pagination is faster", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	results, total, err := testStorage.SearchComments(ctx, models.SearchParams{Query: "keyset pagination", Limit: 10})
+	if err != nil {
+		t.Fatalf("SearchComments failed: %v", err)
+	}
+
+	if total != 1 || len(results) != 1 || results[0].ID != matchID {
+		t.Fatalf("unexpected search results: total=%d %+v", total, results)
+	}
+
+	if len(results[0].Ancestors) != 2 || results[0].Ancestors[0] != rootID || results[0].Ancestors[1] != childID {
+		t.Fatalf("unexpected ancestry: %v", results[0].Ancestors)
+	}
+
+	results, total, err = testStorage.SearchComments(ctx, models.SearchParams{Query: "pagination", Limit: 10})
+	if err != nil {
+		t.Fatalf("SearchComments failed: %v", err)
+	}
+
+	if total != 2 || len(results) != 2 {
+		t.Fatalf("expected 2 matches, got total=%d %+v", total, results)
+	}
+
+	results, total, err = testStorage.SearchComments(ctx, models.SearchParams{Query: "pagination", Limit: 10, Offset: 10})
+	if err != nil {
+		t.Fatalf("SearchComments failed: %v", err)
+	}
+
+	if total != 2 || len(results) != 0 {
+		t.Fatalf("expected an empty page with the full total, got total=%d %+v", total, results)
+	}
+
+}
+
+func TestThreadKey(t *testing.T) {
+
+	setupTest(t)
+	ctx := context.Background()
+
+	postID, err := testStorage.CreateComment(ctx, models.Comment{ThreadKey: "blog/post-1", Content: "On post 1", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	if _, err := testStorage.CreateComment(ctx, models.Comment{Content: "Global", Author: "test"}); err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	replyID, err := testStorage.CreateComment(ctx, models.Comment{ParentID: &postID, Content: "Reply", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	reply, err := testStorage.GetComment(ctx, replyID)
+	if err != nil {
+		t.Fatalf("GetComment failed: %v", err)
+	}
+
+	if reply.ThreadKey != "blog/post-1" {
+		t.Fatalf("expected reply to inherit thread key, got %q", reply.ThreadKey)
+	}
+
+	params := models.QueryParams{ThreadKey: "blog/post-1", Limit: 10}
+
+	roots, err := testStorage.GetRootComments(ctx, params)
+	if err != nil {
+		t.Fatalf("GetRootComments failed: %v", err)
+	}
+
+	if len(roots) != 1 || roots[0].ID != postID {
+		t.Fatalf("unexpected roots for thread: %+v", roots)
+	}
+
+	total, err := testStorage.CountRootComments(ctx, models.QueryParams{})
+	if err != nil {
+		t.Fatalf("CountRootComments failed: %v", err)
+	}
+
+	if total != 1 {
+		t.Fatalf("expected 1 root in the default thread, got %d", total)
+	}
+
+}
+
+func TestVoteComment(t *testing.T) {
+
+	setupTest(t)
+	ctx := context.Background()
+
+	firstID, err := testStorage.CreateComment(ctx, models.Comment{Content: "Meh", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	bestID, err := testStorage.CreateComment(ctx, models.Comment{Content: "Best", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	if _, err := testStorage.VoteComment(ctx, bestID, "alice", 1); err != nil {
+		t.Fatalf("VoteComment failed: %v", err)
+	}
+
+	if _, err := testStorage.VoteComment(ctx, bestID, "bob", 1); err != nil {
+		t.Fatalf("VoteComment failed: %v", err)
+	}
+
+	comment, err := testStorage.VoteComment(ctx, bestID, "alice", -1)
+	if err != nil {
+		t.Fatalf("VoteComment failed: %v", err)
+	}
+
+	if comment.Upvotes != 1 || comment.Downvotes != 1 || comment.Score != 0 {
+		t.Fatalf("expected a changed vote to replace the old one, got %+v", comment)
+	}
+
+	comment, err = testStorage.VoteComment(ctx, bestID, "alice", 0)
+	if err != nil {
+		t.Fatalf("VoteComment failed: %v", err)
+	}
+
+	if comment.Upvotes != 1 || comment.Downvotes != 0 || comment.Score != 1 {
+		t.Fatalf("expected a withdrawn vote to be removed, got %+v", comment)
+	}
+
+	roots, err := testStorage.GetRootComments(ctx, models.QueryParams{Limit: 10, Sort: "top"})
+	if err != nil {
+		t.Fatalf("GetRootComments failed: %v", err)
+	}
+
+	if len(roots) != 2 || roots[0].ID != bestID || roots[1].ID != firstID {
+		t.Fatalf("unexpected top order: %+v", roots)
+	}
+
+	_, err = testStorage.VoteComment(ctx, 999999, "alice", 1)
+	if err != errs.ErrCommentNotFound {
+		t.Fatalf("expected ErrCommentNotFound, got %v", err)
+	}
+
+}
+
+func TestHotSort(t *testing.T) {
+
+	setupTest(t)
+	ctx := context.Background()
+
+	oldID, err := testStorage.CreateComment(ctx, models.Comment{Content: "Old", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	busyID, err := testStorage.CreateComment(ctx, models.Comment{Content: "Busy", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	quietID, err := testStorage.CreateComment(ctx, models.Comment{Content: "Quiet", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	if _, err := testStorage.DB().Master.ExecContext(ctx, `UPDATE comments SET created_at = NOW() - INTERVAL '7 days' WHERE id = $1`, oldID); err != nil {
+		t.Fatalf("failed to age comment: %v", err)
+	}
+
+	for _, voter := range []string{"a", "b", "c"} {
+		if _, err := testStorage.VoteComment(ctx, oldID, voter, 1); err != nil {
+			t.Fatalf("VoteComment failed: %v", err)
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		if _, err := testStorage.CreateComment(ctx, models.Comment{ParentID: &busyID, Content: "Reply", Author: "test"}); err != nil {
+			t.Fatalf("CreateComment failed: %v", err)
+		}
+	}
+
+	roots, err := testStorage.GetRootComments(ctx, models.QueryParams{Limit: 10, Sort: "hot"})
+	if err != nil {
+		t.Fatalf("GetRootComments failed: %v", err)
+	}
+
+	if len(roots) != 3 || roots[0].ID != busyID || roots[1].ID != quietID || roots[2].ID != oldID {
+		t.Fatalf("unexpected hot order: %+v", roots)
+	}
+
+}
+
+func TestReactions(t *testing.T) {
+
+	setupTest(t)
+	ctx := context.Background()
+
+	rootID, err := testStorage.CreateComment(ctx, models.Comment{Content: "Root", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	replyID, err := testStorage.CreateComment(ctx, models.Comment{ParentID: &rootID, Content: "Reply", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	for _, reactor := range []string{"alice", "bob", "alice"} {
+		if _, err := testStorage.AddReaction(ctx, rootID, "👍", reactor); err != nil {
+			t.Fatalf("AddReaction failed: %v", err)
+		}
+	}
+
+	counts, err := testStorage.AddReaction(ctx, replyID, "🎉", "alice")
+	if err != nil {
+		t.Fatalf("AddReaction failed: %v", err)
+	}
+
+	if counts["🎉"] != 1 {
+		t.Fatalf("unexpected reply counts: %v", counts)
+	}
+
+	reactions, err := testStorage.GetReactions(ctx, []int64{rootID, replyID})
+	if err != nil {
+		t.Fatalf("GetReactions failed: %v", err)
+	}
+
+	if reactions[rootID]["👍"] != 2 || reactions[replyID]["🎉"] != 1 {
+		t.Fatalf("unexpected reactions: %v", reactions)
+	}
+
+	counts, err = testStorage.RemoveReaction(ctx, rootID, "👍", "alice")
+	if err != nil {
+		t.Fatalf("RemoveReaction failed: %v", err)
+	}
+
+	if counts["👍"] != 1 {
+		t.Fatalf("expected one reaction left, got %v", counts)
+	}
+
+	_, err = testStorage.AddReaction(ctx, 999999, "👍", "alice")
+	if err != errs.ErrCommentNotFound {
+		t.Fatalf("expected ErrCommentNotFound, got %v", err)
+	}
+
+}
+
+func TestGetUserRole(t *testing.T) {
+
+	setupTest(t)
+	ctx := context.Background()
+
+	if _, err := testStorage.DB().Master.ExecContext(ctx, `
+		INSERT INTO users (subject, role) VALUES ('mod', 'moderator')
+		ON CONFLICT (subject) DO UPDATE SET role = EXCLUDED.role`); err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+
+	role, err := testStorage.GetUserRole(ctx, "mod")
+	if err != nil {
+		t.Fatalf("GetUserRole failed: %v", err)
+	}
+
+	if role != "moderator" {
+		t.Fatalf("expected moderator, got %q", role)
+	}
+
+	_, err = testStorage.GetUserRole(ctx, "nobody")
+	if err != errs.ErrUserNotFound {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+
+}
+
+func TestAPIKeys(t *testing.T) {
+
+	setupTest(t)
+	ctx := context.Background()
+
+	if _, err := testStorage.DB().Master.ExecContext(ctx, `TRUNCATE TABLE api_keys RESTART IDENTITY`); err != nil {
+		t.Fatalf("failed to truncate api_keys: %v", err)
+	}
+
+	created, err := testStorage.CreateAPIKey(ctx, models.APIKey{Name: "shop", Prefix: "hk_abc", Scopes: []string{"create"}, CreatedBy: "root"}, strings.Repeat("a", 64))
+	if err != nil {
+		t.Fatalf("CreateAPIKey failed: %v", err)
+	}
+
+	if created.ID == 0 || created.LastUsedAt != nil || len(created.Scopes) != 1 {
+		t.Fatalf("unexpected key: %+v", created)
+	}
+
+	used, err := testStorage.UseAPIKey(ctx, strings.Repeat("a", 64))
+	if err != nil {
+		t.Fatalf("UseAPIKey failed: %v", err)
+	}
+
+	if used.ID != created.ID || used.LastUsedAt == nil {
+		t.Fatalf("expected last_used_at to be set, got %+v", used)
+	}
+
+	if _, err := testStorage.CreateAPIKey(ctx, models.APIKey{Name: "shop", Prefix: "hk_def", Scopes: []string{}, CreatedBy: "root"}, strings.Repeat("b", 64)); err != errs.ErrKeyNameTaken {
+		t.Fatalf("expected ErrKeyNameTaken for a second live key, got %v", err)
+	}
+
+	if err := testStorage.RevokeAPIKey(ctx, created.ID); err != nil {
+		t.Fatalf("RevokeAPIKey failed: %v", err)
+	}
+
+	if _, err := testStorage.UseAPIKey(ctx, strings.Repeat("a", 64)); err != errs.ErrAPIKeyNotFound {
+		t.Fatalf("expected revoked key to be rejected, got %v", err)
+	}
+
+	if err := testStorage.RevokeAPIKey(ctx, created.ID); err != errs.ErrAPIKeyNotFound {
+		t.Fatalf("expected ErrAPIKeyNotFound, got %v", err)
+	}
+
+	keys, err := testStorage.ListAPIKeys(ctx)
+	if err != nil {
+		t.Fatalf("ListAPIKeys failed: %v", err)
+	}
+
+	if len(keys) != 1 || keys[0].RevokedAt == nil {
+		t.Fatalf("unexpected keys: %+v", keys)
+	}
+
+	if _, err := testStorage.CreateAPIKey(ctx, models.APIKey{Name: "shop", Prefix: "hk_def", Scopes: []string{}, CreatedBy: "root"}, strings.Repeat("b", 64)); err != nil {
+		t.Fatalf("expected the revoked key's name to be reusable, got %v", err)
+	}
+
+}
+
+func TestModeration(t *testing.T) {
+
+	setupTest(t)
+	ctx := context.Background()
+
+	publicID, err := testStorage.CreateComment(ctx, models.Comment{Content: "Public", Author: "test", Status: models.StatusApproved})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	pendingID, err := testStorage.CreateComment(ctx, models.Comment{Content: "Pending", Author: "test", Status: models.StatusPending})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	pendingReplyID, err := testStorage.CreateComment(ctx, models.Comment{ParentID: &publicID, Content: "Pending reply", Author: "test", Status: models.StatusPending})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	if _, err := testStorage.CreateComment(ctx, models.Comment{ParentID: &pendingID, Content: "Hidden parent", Author: "test", Status: models.StatusApproved}); err != errs.ErrParentNotFound {
+		t.Fatalf("expected ErrParentNotFound for a reply to a pending comment, got %v", err)
+	}
+
+	roots, err := testStorage.GetRootComments(ctx, models.QueryParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetRootComments failed: %v", err)
+	}
+
+	if len(roots) != 1 || roots[0].ID != publicID {
+		t.Fatalf("expected only the approved root, got %+v", roots)
+	}
+
+	tree, err := testStorage.GetCommentTrees(ctx, []int64{publicID}, "", models.TreeLimits{})
+	if err != nil {
+		t.Fatalf("GetCommentTrees failed: %v", err)
+	}
+
+	if len(tree) != 1 || tree[0].ChildrenCount != 0 {
+		t.Fatalf("expected the pending reply to be hidden, got %+v", tree)
+	}
+
+	if _, err := testStorage.GetRevisions(ctx, pendingReplyID); err != errs.ErrCommentNotFound {
+		t.Fatalf("expected ErrCommentNotFound for the revisions of a pending comment, got %v", err)
+	}
+
+	ancestors, err := testStorage.GetAncestors(ctx, pendingReplyID)
+	if err != nil {
+		t.Fatalf("GetAncestors failed: %v", err)
+	}
+
+	if len(ancestors) != 0 {
+		t.Fatalf("expected no ancestors for a pending comment, got %+v", ancestors)
+	}
+
+	queue, total, err := testStorage.GetPendingComments(ctx, models.QueryParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetPendingComments failed: %v", err)
+	}
+
+	if total != 2 || len(queue) != 2 || queue[0].ID != pendingID || queue[1].ID != pendingReplyID {
+		t.Fatalf("unexpected queue: %+v", queue)
+	}
+
+	approved, err := testStorage.ModerateComment(ctx, pendingReplyID, models.StatusApproved)
+	if err != nil {
+		t.Fatalf("ModerateComment failed: %v", err)
+	}
+
+	if approved.Status != models.StatusApproved {
+		t.Fatalf("expected approved status, got %q", approved.Status)
+	}
+
+	if _, err := testStorage.ModerateComment(ctx, pendingReplyID, models.StatusRejected); err != errs.ErrCommentNotFound {
+		t.Fatalf("expected ErrCommentNotFound for a comment outside the queue, got %v", err)
+	}
+
+	tree, err = testStorage.GetCommentTrees(ctx, []int64{publicID}, "", models.TreeLimits{})
+	if err != nil {
+		t.Fatalf("GetCommentTrees failed: %v", err)
+	}
+
+	if len(tree) != 2 {
+		t.Fatalf("expected the approved reply in the tree, got %+v", tree)
+	}
+
+}
+
+func TestReports(t *testing.T) {
+
+	setupTest(t)
+	ctx := context.Background()
+
+	quietID, err := testStorage.CreateComment(ctx, models.Comment{Content: "Quiet", Author: "test", Status: models.StatusApproved})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	loudID, err := testStorage.CreateComment(ctx, models.Comment{Content: "Loud", Author: "test", Status: models.StatusApproved})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	if _, err := testStorage.ReportComment(ctx, models.Report{CommentID: quietID, Reporter: "alice", Reason: models.ReasonOffTopic}, 2); err != nil {
+		t.Fatalf("ReportComment failed: %v", err)
+	}
+
+	// Reporting twice counts once.
+	for range 2 {
+		comment, err := testStorage.ReportComment(ctx, models.Report{CommentID: loudID, Reporter: "alice", Reason: models.ReasonSpam}, 2)
+		if err != nil {
+			t.Fatalf("ReportComment failed: %v", err)
+		}
+		if comment.Status != models.StatusApproved {
+			t.Fatalf("expected comment to stay approved, got %q", comment.Status)
+		}
+	}
+
+	comment, err := testStorage.ReportComment(ctx, models.Report{CommentID: loudID, Reporter: "bob", Reason: models.ReasonHarassment, Note: "rude"}, 2)
+	if err != nil {
+		t.Fatalf("ReportComment failed: %v", err)
+	}
+
+	if comment.Status != models.StatusHidden {
+		t.Fatalf("expected comment to be hidden, got %q", comment.Status)
+	}
+
+	if _, err := testStorage.ReportComment(ctx, models.Report{CommentID: loudID, Reporter: "carol", Reason: models.ReasonSpam}, 2); err != errs.ErrCommentNotFound {
+		t.Fatalf("expected ErrCommentNotFound for a hidden comment, got %v", err)
+	}
+
+	roots, err := testStorage.GetRootComments(ctx, models.QueryParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetRootComments failed: %v", err)
+	}
+
+	if len(roots) != 1 || roots[0].ID != quietID {
+		t.Fatalf("expected the hidden comment to be left out, got %+v", roots)
+	}
+
+	reported, total, err := testStorage.GetReportedComments(ctx, models.QueryParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetReportedComments failed: %v", err)
+	}
+
+	if total != 2 || len(reported) != 2 || reported[0].ID != loudID || reported[1].ID != quietID {
+		t.Fatalf("expected the most reported comment first, got %+v", reported)
+	}
+
+	if reported[0].Reports != 2 || reported[0].Reasons[models.ReasonSpam] != 1 || reported[0].Reasons[models.ReasonHarassment] != 1 {
+		t.Fatalf("unexpected report summary: %+v", reported[0])
+	}
+
+	if _, err := testStorage.ModerateComment(ctx, loudID, models.StatusApproved); err != nil {
+		t.Fatalf("ModerateComment failed: %v", err)
+	}
+
+	// Approving the quiet comment dismisses its report.
+	if _, err := testStorage.ModerateComment(ctx, quietID, models.StatusApproved); err != nil {
+		t.Fatalf("ModerateComment failed: %v", err)
+	}
+
+	reported, total, err = testStorage.GetReportedComments(ctx, models.QueryParams{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetReportedComments failed: %v", err)
+	}
+
+	if total != 0 || len(reported) != 0 {
+		t.Fatalf("expected reviewed reports to leave the listing, got %+v", reported)
+	}
+
+	if _, err := testStorage.ModerateComment(ctx, quietID, models.StatusRejected); err != errs.ErrCommentNotFound {
+		t.Fatalf("expected ErrCommentNotFound once the reports are reviewed, got %v", err)
+	}
+
+	// Only reports made after the review count towards hiding it again.
+	comment, err = testStorage.ReportComment(ctx, models.Report{CommentID: loudID, Reporter: "carol", Reason: models.ReasonSpam}, 2)
+	if err != nil {
+		t.Fatalf("ReportComment failed: %v", err)
+	}
+
+	if comment.Status != models.StatusApproved {
+		t.Fatalf("expected comment to stay approved, got %q", comment.Status)
+	}
+
+	comment, err = testStorage.ReportComment(ctx, models.Report{CommentID: loudID, Reporter: "alice", Reason: models.ReasonSpam}, 2)
+	if err != nil {
+		t.Fatalf("ReportComment failed: %v", err)
+	}
+
+	if comment.Status != models.StatusHidden {
+		t.Fatalf("expected two new reports to hide the comment again, got %q", comment.Status)
+	}
+
+	// A lowered threshold applies to comments already past it.
+	if _, err := testStorage.ReportComment(ctx, models.Report{CommentID: quietID, Reporter: "dave", Reason: models.ReasonSpam}, 5); err != nil {
+		t.Fatalf("ReportComment failed: %v", err)
+	}
+
+	comment, err = testStorage.ReportComment(ctx, models.Report{CommentID: quietID, Reporter: "erin", Reason: models.ReasonSpam}, 1)
+	if err != nil {
+		t.Fatalf("ReportComment failed: %v", err)
+	}
+
+	if comment.Status != models.StatusHidden {
+		t.Fatalf("expected the comment to be hidden under the lower threshold, got %q", comment.Status)
+	}
+
+}
+
+func TestHasRecentDuplicate(t *testing.T) {
+
+	setupTest(t)
+	ctx := context.Background()
+
+	for _, content := range []string{" Hello ", "Bye\n", "\tSee you\r\n"} {
+		if _, err := testStorage.CreateComment(ctx, models.Comment{Content: content, Author: "alice", Status: models.StatusApproved}); err != nil {
+			t.Fatalf("CreateComment failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		author, content string
+		window          time.Duration
+		want            bool
+	}{
+		{"alice", "Hello", time.Minute, true},
+		{"alice", "hello", time.Minute, false},
+		{"bob", "Hello", time.Minute, false},
+		{"alice", "Hello", time.Nanosecond, false},
+		{"alice", "Bye", time.Minute, true},
+		{"alice", "See you", time.Minute, true},
+	}
+
+	for _, tt := range tests {
+		got, err := testStorage.HasRecentDuplicate(ctx, tt.author, tt.content, tt.window)
+		if err != nil {
+			t.Fatalf("HasRecentDuplicate failed: %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("HasRecentDuplicate(%q, %q, %v) = %v, want %v", tt.author, tt.content, tt.window, got, tt.want)
+		}
+	}
+
+}
+
+func TestChallenges(t *testing.T) {
+
+	setupTest(t)
+	ctx := context.Background()
+
+	if _, err := testStorage.DB().Master.ExecContext(ctx, `TRUNCATE TABLE used_challenges`); err != nil {
+		t.Fatalf("failed to truncate used_challenges: %v", err)
+	}
+
+	for _, content := range []string{"one", "two"} {
+		if _, err := testStorage.CreateComment(ctx, models.Comment{Content: content, Author: "test", Status: models.StatusPending}); err != nil {
+			t.Fatalf("CreateComment failed: %v", err)
+		}
+	}
+
+	recent, err := testStorage.CountRecentComments(ctx, time.Minute)
+	if err != nil {
+		t.Fatalf("CountRecentComments failed: %v", err)
+	}
+
+	if recent != 2 {
+		t.Fatalf("expected 2 recent comments, got %d", recent)
+	}
+
+	id := strings.Repeat("a", 32)
+	expiresAt := time.Now().Add(time.Minute)
+
+	if err := testStorage.UseChallenge(ctx, id, expiresAt); err != nil {
+		t.Fatalf("UseChallenge failed: %v", err)
+	}
+
+	if err := testStorage.UseChallenge(ctx, id, expiresAt); err != errs.ErrProofReused {
+		t.Fatalf("expected ErrProofReused, got %v", err)
+	}
+
+}
+
+func TestThreadLock(t *testing.T) {
+
+	setupTest(t)
+	ctx := context.Background()
+
+	staleID, err := testStorage.CreateComment(ctx, models.Comment{Content: "Stale", Author: "test", Status: models.StatusApproved})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	activeID, err := testStorage.CreateComment(ctx, models.Comment{Content: "Active", Author: "test", Status: models.StatusApproved})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	replyID, err := testStorage.CreateComment(ctx, models.Comment{ParentID: &activeID, Content: "Fresh reply", Author: "test", Status: models.StatusApproved})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	if _, err := testStorage.DB().Master.ExecContext(ctx, `
+		UPDATE comments SET created_at = NOW() - INTERVAL '40 days', updated_at = NOW() - INTERVAL '40 days'
+		WHERE id IN ($1, $2)`, staleID, activeID); err != nil {
+		t.Fatalf("failed to age comments: %v", err)
+	}
+
+	locked, err := testStorage.LockInactiveThreads(ctx, 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("LockInactiveThreads failed: %v", err)
+	}
+
+	if locked != 1 {
+		t.Fatalf("expected 1 thread to be locked, got %d", locked)
+	}
+
+	stale, err := testStorage.GetComment(ctx, staleID)
+	if err != nil {
+		t.Fatalf("GetComment failed: %v", err)
+	}
+
+	if stale.LockedAt == nil {
+		t.Fatalf("expected the stale thread to be locked")
+	}
+
+	comment, err := testStorage.LockComment(ctx, activeID)
+	if err != nil {
+		t.Fatalf("LockComment failed: %v", err)
+	}
+
+	if comment.LockedAt == nil {
+		t.Fatalf("expected the thread to be locked")
+	}
+
+	ancestors, err := testStorage.GetAncestors(ctx, replyID)
+	if err != nil {
+		t.Fatalf("GetAncestors failed: %v", err)
+	}
+
+	if len(ancestors) != 1 || ancestors[0].LockedAt == nil {
+		t.Fatalf("expected the locked root among the ancestors, got %+v", ancestors)
+	}
+
+	if _, err := testStorage.CreateComment(ctx, models.Comment{ParentID: &activeID, Content: "Late reply", Author: "test"}); err != errs.ErrThreadLocked {
+		t.Fatalf("expected ErrThreadLocked for a reply to the locked root, got %v", err)
+	}
+
+	if _, err := testStorage.CreateComment(ctx, models.Comment{ParentID: &replyID, Content: "Nested reply", Author: "test"}); err != errs.ErrThreadLocked {
+		t.Fatalf("expected ErrThreadLocked for a reply below the locked root, got %v", err)
+	}
+
+	var replies int
+	if err := testStorage.DB().Master.QueryRowContext(ctx, `SELECT reply_count FROM comments WHERE id = $1`, replyID).Scan(&replies); err != nil {
+		t.Fatalf("failed to read reply count: %v", err)
+	}
+
+	if replies != 0 {
+		t.Fatalf("expected refused replies not to be counted, got %d", replies)
+	}
+
+	comment, err = testStorage.UnlockComment(ctx, activeID)
+	if err != nil {
+		t.Fatalf("UnlockComment failed: %v", err)
+	}
+
+	if comment.LockedAt != nil {
+		t.Fatalf("expected the thread to be unlocked")
+	}
+
+	if _, err := testStorage.CreateComment(ctx, models.Comment{ParentID: &replyID, Content: "Nested reply", Author: "test"}); err != nil {
+		t.Fatalf("expected replies once the thread is unlocked, got %v", err)
+	}
+
+	if _, err := testStorage.LockComment(ctx, 9999); err != errs.ErrCommentNotFound {
+		t.Fatalf("expected ErrCommentNotFound, got %v", err)
+	}
+
+}
+
+func TestPinnedComments(t *testing.T) {
+
+	setupTest(t)
+	ctx := context.Background()
+
+	var ids []int64
+	for i := 0; i < 4; i++ {
+		id, err := testStorage.CreateComment(ctx, models.Comment{Content: fmt.Sprintf("Root%d", i), Author: "test"})
+		if err != nil {
+			t.Fatalf("CreateComment failed: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	if _, err := testStorage.PinComment(ctx, ids[1]); err != nil {
+		t.Fatalf("PinComment failed: %v", err)
+	}
+
+	pinned, err := testStorage.PinComment(ctx, ids[2])
+	if err != nil {
+		t.Fatalf("PinComment failed: %v", err)
+	}
+
+	if !pinned.Pinned || pinned.PinnedAt == nil {
+		t.Fatalf("expected the comment to be pinned, got %+v", pinned)
+	}
+
+	replyID, err := testStorage.CreateComment(ctx, models.Comment{ParentID: &ids[0], Content: "Reply", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	if _, err := testStorage.PinComment(ctx, replyID); err != errs.ErrCommentNotFound {
+		t.Fatalf("expected ErrCommentNotFound when pinning a reply, got %v", err)
+	}
+
+	// Most recently pinned first, then the rest in the requested order.
+	want := map[string][]int64{
+		"created_at_desc": {ids[2], ids[1], ids[3], ids[0]},
+		"created_at_asc":  {ids[2], ids[1], ids[0], ids[3]},
+		"top":             {ids[2], ids[1], ids[3], ids[0]},
+	}
+
+	for sort, order := range want {
+
+		roots, err := testStorage.GetRootComments(ctx, models.QueryParams{Limit: 10, Sort: sort})
+		if err != nil {
+			t.Fatalf("GetRootComments failed: %v", err)
+		}
+
+		var got []int64
+		for _, r := range roots {
+			got = append(got, r.ID)
+		}
+
+		if fmt.Sprint(got) != fmt.Sprint(order) {
+			t.Fatalf("%s: expected %v, got %v", sort, order, got)
+		}
+
+		// Offset pages and cursor pages both walk the same order.
+		for _, useCursor := range []bool{false, true} {
+
+			params := models.QueryParams{Limit: 1, Sort: sort}
+			var seen []int64
+
+			for page := 0; page < len(order); page++ {
+				params.Offset = page
+				roots, err := testStorage.GetRootComments(ctx, params)
+				if err != nil {
+					t.Fatalf("GetRootComments failed: %v", err)
+				}
+				if len(roots) != 1 {
+					break
+				}
+				seen = append(seen, roots[0].ID)
+				if useCursor && sort != "top" {
+					last := roots[0]
+					params.Offset = 0
+					params.Cursor = &models.Cursor{CreatedAt: last.CreatedAt, ID: last.ID, PinnedAt: last.PinnedAt}
+				}
+			}
+
+			if fmt.Sprint(seen) != fmt.Sprint(order) {
+				t.Fatalf("%s (cursor %v): expected %v across pages, got %v", sort, useCursor, order, seen)
+			}
+
+		}
+
+	}
+
+	unpinned, err := testStorage.UnpinComment(ctx, ids[1])
+	if err != nil {
+		t.Fatalf("UnpinComment failed: %v", err)
+	}
+
+	if unpinned.Pinned {
+		t.Fatalf("expected the comment to be unpinned")
+	}
+
+}
+
+func TestMoveComment(t *testing.T) {
+
+	setupTest(t)
+	ctx := context.Background()
+
+	rootID, err := testStorage.CreateComment(ctx, models.Comment{ThreadKey: "post-1", Content: "Root", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	otherID, err := testStorage.CreateComment(ctx, models.Comment{ThreadKey: "post-2", Content: "Other root", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	childID, err := testStorage.CreateComment(ctx, models.Comment{ParentID: &rootID, Content: "Child", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	grandchildID, err := testStorage.CreateComment(ctx, models.Comment{ParentID: &childID, Content: "Grandchild", Author: "test"})
+	if err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+
+	if _, err := testStorage.MoveComment(ctx, rootID, &grandchildID); err != errs.ErrInvalidMove {
+		t.Fatalf("expected ErrInvalidMove when moving under a descendant, got %v", err)
+	}
+
+	if _, err := testStorage.MoveComment(ctx, childID, &childID); err != errs.ErrInvalidMove {
+		t.Fatalf("expected ErrInvalidMove when moving under itself, got %v", err)
+	}
+
+	missingID := int64(9999)
+	if _, err := testStorage.MoveComment(ctx, childID, &missingID); err != errs.ErrParentNotFound {
+		t.Fatalf("expected ErrParentNotFound, got %v", err)
+	}
+
+	if _, err := testStorage.MoveComment(ctx, 9999, &rootID); err != errs.ErrCommentNotFound {
+		t.Fatalf("expected ErrCommentNotFound, got %v", err)
+	}
+
+	moved, err := testStorage.MoveComment(ctx, childID, &otherID)
+	if err != nil {
+		t.Fatalf("MoveComment failed: %v", err)
+	}
+
+	if moved.ParentID == nil || *moved.ParentID != otherID || moved.ThreadKey != "post-2" {
+		t.Fatalf("expected the child under the other root in its thread, got %+v", moved)
+	}
+
+	tree, err := testStorage.GetCommentTrees(ctx, []int64{otherID}, "", models.TreeLimits{})
+	if err != nil {
+		t.Fatalf("GetCommentTrees failed: %v", err)
+	}
+
+	if len(tree) != 3 {
+		t.Fatalf("expected the subtree to move along, got %d comments", len(tree))
+	}
+
+	for _, c := range tree {
+		if c.ThreadKey != "post-2" {
+			t.Fatalf("expected comment %d in thread post-2, got %q", c.ID, c.ThreadKey)
+		}
+	}
+
+	var rootReplies, otherReplies int
+	if err := testStorage.DB().Master.QueryRowContext(ctx, `
+		SELECT
+			(SELECT reply_count FROM comments WHERE id = $1),
+			(SELECT reply_count FROM comments WHERE id = $2)`, rootID, otherID).Scan(&rootReplies, &otherReplies); err != nil {
+		t.Fatalf("failed to read reply counts: %v", err)
+	}
+
+	if rootReplies != 0 || otherReplies != 1 {
+		t.Fatalf("expected reply counts 0 and 1, got %d and %d", rootReplies, otherReplies)
+	}
+
+	promoted, err := testStorage.MoveComment(ctx, grandchildID, nil)
+	if err != nil {
+		t.Fatalf("MoveComment failed: %v", err)
+	}
+
+	if promoted.ParentID != nil || promoted.ThreadKey != "post-2" {
+		t.Fatalf("expected a root in thread post-2, got %+v", promoted)
+	}
+
+	if _, err := testStorage.PinComment(ctx, promoted.ID); err != nil {
+		t.Fatalf("PinComment failed: %v", err)
+	}
+
+	demoted, err := testStorage.MoveComment(ctx, promoted.ID, &rootID)
+	if err != nil {
+		t.Fatalf("MoveComment failed: %v", err)
+	}
+
+	if demoted.Pinned || demoted.ThreadKey != "post-1" {
+		t.Fatalf("expected an unpinned reply in thread post-1, got %+v", demoted)
+	}
+
+}
+
 func TestClose(t *testing.T) {
 	log, _ := logger.NewLogger(config.Logger{Debug: true})
 	db, _ := dbpg.New(fmt.Sprintf("host=postgres-test port=5432 user=%s password=%s dbname=hermes_test sslmode=disable",
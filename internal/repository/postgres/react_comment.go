@@ -0,0 +1,87 @@
+package postgres
+
+import (
+	"Hermes/internal/errs"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// AddReaction records reactor's emoji reaction on a comment. Reacting twice
+// with the same emoji is a no-op. It returns the comment's reaction counts.
+func (s *Storage) AddReaction(ctx context.Context, commentID int64, emoji, reactor string) (map[string]int, error) {
+	return s.react(ctx, commentID, `
+
+		INSERT INTO comment_reactions (comment_id, emoji, reactor)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING`,
+
+		emoji, reactor)
+}
+
+// RemoveReaction withdraws reactor's emoji reaction from a comment, if any.
+// It returns the comment's reaction counts.
+func (s *Storage) RemoveReaction(ctx context.Context, commentID int64, emoji, reactor string) (map[string]int, error) {
+	return s.react(ctx, commentID, `
+
+		DELETE FROM comment_reactions
+		WHERE comment_id = $1 AND emoji = $2 AND reactor = $3`,
+
+		emoji, reactor)
+}
+
+// react runs query against a live comment and recounts its reactions in the
+// same transaction.
+func (s *Storage) react(ctx context.Context, commentID int64, query, emoji, reactor string) (map[string]int, error) {
+
+	var counts map[string]int
+
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+
+		var id int64
+		if err := tx.QueryRowContext(ctx, `
+
+			SELECT id FROM comments
+			WHERE id = $1 AND deleted_at IS NULL AND status = 'approved'
+			FOR SHARE`,
+
+			commentID).Scan(&id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errs.ErrCommentNotFound
+			}
+			return fmt.Errorf("failed to lock comment: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, query, commentID, emoji, reactor); err != nil {
+			return fmt.Errorf("failed to save reaction: %w", err)
+		}
+
+		rows, err := tx.QueryContext(ctx, reactionCountsQuery, pq.Array([]int64{commentID}))
+		if err != nil {
+			return fmt.Errorf("failed to count reactions: %w", err)
+		}
+		defer func() { _ = rows.Close() }()
+
+		reactions, err := scanReactions(rows)
+		if err != nil {
+			return err
+		}
+
+		counts = reactions[commentID]
+		if counts == nil {
+			counts = map[string]int{}
+		}
+
+		return nil
+
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+
+}
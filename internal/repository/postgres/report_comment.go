@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ReportComment records a report against a live comment. A reporter counts
+// once per comment; reporting again replaces the reason and note and, if a
+// moderator already reviewed the report, opens it again. When the number of
+// open reports reaches threshold the comment is hidden, so a comment a
+// moderator approved is only hidden again by new reports. A zero threshold
+// disables hiding.
+func (s *Storage) ReportComment(ctx context.Context, report models.Report, threshold int) (models.Comment, error) {
+
+	var comment models.Comment
+
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+
+		var id int64
+		if err := tx.QueryRowContext(ctx, `
+
+			SELECT id FROM comments
+			WHERE id = $1 AND deleted_at IS NULL AND status = 'approved'
+			FOR UPDATE`,
+
+			report.CommentID).Scan(&id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errs.ErrCommentNotFound
+			}
+			return fmt.Errorf("failed to lock comment: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+
+			INSERT INTO comment_reports (comment_id, reporter, reason, note)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (comment_id, reporter)
+			DO UPDATE SET reason = EXCLUDED.reason, note = EXCLUDED.note,
+			    created_at = CASE WHEN comment_reports.reviewed_at IS NULL THEN comment_reports.created_at ELSE NOW() END,
+			    reviewed_at = NULL`,
+
+			report.CommentID, report.Reporter, report.Reason, report.Note); err != nil {
+			return fmt.Errorf("failed to save report: %w", err)
+		}
+
+		updated, err := scanComment(tx.QueryRowContext(ctx, `
+
+			UPDATE comments
+			SET status = CASE
+			    WHEN $2 > 0 AND (SELECT COUNT(*) FROM comment_reports WHERE comment_id = $1 AND reviewed_at IS NULL) >= $2 THEN 'hidden'
+			    ELSE status
+			END
+			WHERE id = $1
+			RETURNING `+commentColumns,
+
+			report.CommentID, threshold))
+		if err != nil {
+			return fmt.Errorf("failed to update comment: %w", err)
+		}
+
+		comment = updated
+		return nil
+
+	})
+	if err != nil {
+		return models.Comment{}, err
+	}
+
+	return comment, nil
+
+}
@@ -0,0 +1,39 @@
+package postgres
+
+import (
+	"Hermes/internal/errs"
+	"context"
+	"fmt"
+
+	"github.com/wb-go/wbf/retry"
+)
+
+func (s *Storage) RevokeAPIKey(ctx context.Context, id int64) error {
+
+	result, err := s.db.ExecWithRetry(ctx, retry.Strategy{
+		Attempts: s.config.QueryRetryStrategy.Attempts,
+		Delay:    s.config.QueryRetryStrategy.Delay,
+		Backoff:  s.config.QueryRetryStrategy.Backoff,
+	}, `
+
+		UPDATE api_keys
+		SET revoked_at = NOW()
+		WHERE id = $1 AND revoked_at IS NULL`,
+
+		id)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get number of affected rows: %w", err)
+	}
+
+	if rows == 0 {
+		return errs.ErrAPIKeyNotFound
+	}
+
+	return nil
+
+}
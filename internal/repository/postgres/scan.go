@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"Hermes/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// commentColumns lists the comments table columns in the order scanComment expects them.
+const commentColumns = "id, parent_id, thread_key, content, author, status, upvotes, downvotes, created_at, updated_at, deleted_at, locked_at, pinned_at"
+
+// apiKeyColumns lists the api_keys table columns in the order scanAPIKey expects them.
+const apiKeyColumns = "id, name, prefix, scopes, created_by, created_at, last_used_at, revoked_at"
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanComment scans commentColumns into a comment; extra receives any
+// columns selected after them.
+func scanComment(row scanner, extra ...any) (models.Comment, error) {
+	var comment models.Comment
+	dest := []any{
+		&comment.ID,
+		&comment.ParentID,
+		&comment.ThreadKey,
+		&comment.Content,
+		&comment.Author,
+		&comment.Status,
+		&comment.Upvotes,
+		&comment.Downvotes,
+		&comment.CreatedAt,
+		&comment.UpdatedAt,
+		&comment.DeletedAt,
+		&comment.LockedAt,
+		&comment.PinnedAt,
+	}
+	err := row.Scan(append(dest, extra...)...)
+	comment.Score = comment.Upvotes - comment.Downvotes
+	comment.Pinned = comment.PinnedAt != nil
+	return comment, err
+}
+
+func scanAPIKey(row scanner) (models.APIKey, error) {
+	var key models.APIKey
+	err := row.Scan(
+		&key.ID,
+		&key.Name,
+		&key.Prefix,
+		pq.Array(&key.Scopes),
+		&key.CreatedBy,
+		&key.CreatedAt,
+		&key.LastUsedAt,
+		&key.RevokedAt,
+	)
+	return key, err
+}
@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"Hermes/internal/models"
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/wb-go/wbf/retry"
+)
+
+// SearchComments runs a ranked full-text search over live, approved comments and returns
+// one page of matches along with the total number of matches. The total is counted
+// separately so that it stays correct for pages past the last match.
+func (s *Storage) SearchComments(ctx context.Context, params models.SearchParams) ([]models.SearchResult, int, error) {
+
+	row, err := s.db.QueryRowWithRetry(ctx, retry.Strategy{
+		Attempts: s.config.QueryRetryStrategy.Attempts,
+		Delay:    s.config.QueryRetryStrategy.Delay,
+		Backoff:  s.config.QueryRetryStrategy.Backoff,
+	}, `
+
+		SELECT COUNT(*)
+		FROM comments c, websearch_to_tsquery('simple', $1) AS q(query)
+		WHERE c.search_vector @@ q.query AND c.deleted_at IS NULL AND c.status = 'approved' AND c.thread_key = $2
+
+	`, params.Query, params.ThreadKey)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to execute count query: %w", err)
+	}
+
+	var total int
+	if err := row.Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to scan count: %w", err)
+	}
+
+	rows, err := s.db.QueryWithRetry(ctx, retry.Strategy{
+		Attempts: s.config.QueryRetryStrategy.Attempts,
+		Delay:    s.config.QueryRetryStrategy.Delay,
+		Backoff:  s.config.QueryRetryStrategy.Backoff,
+	}, `
+
+		WITH RECURSIVE matches AS (
+
+		SELECT c.*, ts_rank(c.search_vector, q.query) AS rank
+		FROM comments c, websearch_to_tsquery('simple', $1) AS q(query)
+		WHERE c.search_vector @@ q.query AND c.deleted_at IS NULL AND c.status = 'approved' AND c.thread_key = $4
+		ORDER BY rank DESC, c.created_at DESC, c.id DESC
+		LIMIT $2 OFFSET $3
+
+		), ancestry AS (
+
+		SELECT m.id AS match_id, m.parent_id AS ancestor_id, 1 AS depth
+		FROM matches m
+		WHERE m.parent_id IS NOT NULL
+
+		UNION ALL
+
+		SELECT a.match_id, c.parent_id, a.depth + 1
+		FROM ancestry a
+		JOIN comments c ON c.id = a.ancestor_id
+		WHERE c.parent_id IS NOT NULL
+
+		)
+
+		SELECT `+commentColumns+`, rank,
+			COALESCE((SELECT array_agg(a.ancestor_id ORDER BY a.depth DESC)
+			          FROM ancestry a
+			          WHERE a.match_id = m.id), '{}')
+		FROM matches m
+		ORDER BY rank DESC, created_at DESC, id DESC
+
+	`, params.Query, params.Limit, params.Offset, params.ThreadKey)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var results []models.SearchResult
+
+	for rows.Next() {
+
+		var r models.SearchResult
+		comment, err := scanComment(rows, &r.Rank, pq.Array(&r.Ancestors))
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan row: %w", err)
+		}
+		r.Comment = comment
+		results = append(results, r)
+
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to iterate rows: %w", err)
+	}
+
+	return results, total, nil
+
+}
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/wb-go/wbf/retry"
+)
+
+func (s *Storage) UpdateComment(ctx context.Context, comment models.Comment) (models.Comment, error) {
+
+	row, err := s.db.QueryRowWithRetry(ctx, retry.Strategy{
+		Attempts: s.config.QueryRetryStrategy.Attempts,
+		Delay:    s.config.QueryRetryStrategy.Delay,
+		Backoff:  s.config.QueryRetryStrategy.Backoff}, `
+		
+		WITH previous AS (
+			SELECT id, content, author, updated_at
+			FROM comments
+			WHERE id = $1 AND deleted_at IS NULL
+			FOR UPDATE
+		), revision AS (
+			INSERT INTO comment_revisions (comment_id, content, author, created_at)
+			SELECT id, content, author, updated_at
+			FROM previous
+		)
+		UPDATE comments
+		SET content = $2, author = $3, updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING `+commentColumns,
+		comment.ID, comment.Content, comment.Author)
+	if err != nil {
+		return models.Comment{}, err
+	}
+
+	updated, err := scanComment(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Comment{}, errs.ErrCommentNotFound
+		}
+		return models.Comment{}, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	return updated, nil
+
+}
@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/wb-go/wbf/retry"
+)
+
+// UseAPIKey looks up the live key with the given hash and records that it
+// was used.
+func (s *Storage) UseAPIKey(ctx context.Context, hash string) (models.APIKey, error) {
+
+	row, err := s.db.QueryRowWithRetry(ctx, retry.Strategy{
+		Attempts: s.config.QueryRetryStrategy.Attempts,
+		Delay:    s.config.QueryRetryStrategy.Delay,
+		Backoff:  s.config.QueryRetryStrategy.Backoff,
+	}, `
+
+		UPDATE api_keys
+		SET last_used_at = NOW()
+		WHERE key_hash = $1 AND revoked_at IS NULL
+		RETURNING `+apiKeyColumns,
+
+		hash)
+	if err != nil {
+		return models.APIKey{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	key, err := scanAPIKey(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.APIKey{}, errs.ErrAPIKeyNotFound
+		}
+		return models.APIKey{}, fmt.Errorf("failed to scan row: %w", err)
+	}
+
+	return key, nil
+
+}
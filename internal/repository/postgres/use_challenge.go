@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"Hermes/internal/errs"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wb-go/wbf/retry"
+)
+
+// UseChallenge marks a proof-of-work challenge as spent until it expires.
+// Spending it twice returns ErrProofReused. Expired entries are purged on
+// the way, since an expired challenge is rejected anyway.
+func (s *Storage) UseChallenge(ctx context.Context, id string, expiresAt time.Time) error {
+
+	result, err := s.db.ExecWithRetry(ctx, retry.Strategy{
+		Attempts: s.config.QueryRetryStrategy.Attempts,
+		Delay:    s.config.QueryRetryStrategy.Delay,
+		Backoff:  s.config.QueryRetryStrategy.Backoff,
+	}, `
+
+		WITH purged AS (
+		    DELETE FROM used_challenges WHERE expires_at < NOW()
+		)
+		INSERT INTO used_challenges (id, expires_at)
+		VALUES ($1, $2)
+		ON CONFLICT (id) DO NOTHING`,
+
+		id, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to execute query: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get number of affected rows: %w", err)
+	}
+
+	if rows == 0 {
+		return errs.ErrProofReused
+	}
+
+	return nil
+
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// VoteComment records voter's vote on a comment, replacing any earlier vote
+// from the same voter; a zero value withdraws the vote. The comment's vote
+// counters are recounted in the same transaction.
+func (s *Storage) VoteComment(ctx context.Context, commentID int64, voter string, value int) (models.Comment, error) {
+
+	var comment models.Comment
+
+	err := s.withTx(ctx, func(tx *sql.Tx) error {
+
+		var id int64
+		if err := tx.QueryRowContext(ctx, `
+
+			SELECT id FROM comments
+			WHERE id = $1 AND deleted_at IS NULL AND status = 'approved'
+			FOR UPDATE`,
+
+			commentID).Scan(&id); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errs.ErrCommentNotFound
+			}
+			return fmt.Errorf("failed to lock comment: %w", err)
+		}
+
+		query := `
+
+			INSERT INTO comment_votes (comment_id, voter, value)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (comment_id, voter)
+			DO UPDATE SET value = EXCLUDED.value, updated_at = NOW()`
+
+		args := []any{commentID, voter, value}
+
+		if value == 0 {
+			query, args = `
+
+			DELETE FROM comment_votes
+			WHERE comment_id = $1 AND voter = $2`, args[:2]
+		}
+
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to save vote: %w", err)
+		}
+
+		updated, err := scanComment(tx.QueryRowContext(ctx, `
+
+			UPDATE comments
+			SET upvotes   = (SELECT COUNT(*) FROM comment_votes WHERE comment_id = $1 AND value = 1),
+			    downvotes = (SELECT COUNT(*) FROM comment_votes WHERE comment_id = $1 AND value = -1)
+			WHERE id = $1
+			RETURNING `+commentColumns,
+
+			commentID))
+		if err != nil {
+			return fmt.Errorf("failed to recount votes: %w", err)
+		}
+
+		comment = updated
+		return nil
+
+	})
+	if err != nil {
+		return models.Comment{}, err
+	}
+
+	return comment, nil
+
+}
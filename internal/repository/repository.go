@@ -7,6 +7,7 @@ import (
 	"Hermes/internal/repository/postgres"
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/wb-go/wbf/dbpg"
 )
@@ -14,9 +15,38 @@ import (
 type Storage interface {
 	Close()
 	CreateComment(ctx context.Context, comment models.Comment) (int64, error)
+	GetComment(ctx context.Context, id int64) (models.Comment, error)
+	HasRecentDuplicate(ctx context.Context, author, content string, window time.Duration) (bool, error)
+	CountRecentComments(ctx context.Context, window time.Duration) (int, error)
+	UseChallenge(ctx context.Context, id string, expiresAt time.Time) error
+	GetAncestors(ctx context.Context, id int64) ([]models.Comment, error)
 	GetRootComments(ctx context.Context, queryParams models.QueryParams) ([]models.Comment, error)
-	GetCommentTree(ctx context.Context, id int64) ([]models.Comment, error)
+	CountRootComments(ctx context.Context, queryParams models.QueryParams) (int, error)
+	GetCommentTrees(ctx context.Context, rootIDs []int64, sort string, limits models.TreeLimits) ([]models.Comment, error)
+	GetChildren(ctx context.Context, parentID int64, queryParams models.QueryParams) ([]models.Comment, int, error)
+	UpdateComment(ctx context.Context, comment models.Comment) (models.Comment, error)
+	GetRevisions(ctx context.Context, commentID int64) ([]models.Revision, error)
+	VoteComment(ctx context.Context, commentID int64, voter string, value int) (models.Comment, error)
+	AddReaction(ctx context.Context, commentID int64, emoji, reactor string) (map[string]int, error)
+	RemoveReaction(ctx context.Context, commentID int64, emoji, reactor string) (map[string]int, error)
+	GetReactions(ctx context.Context, ids []int64) (map[int64]map[string]int, error)
+	SearchComments(ctx context.Context, searchParams models.SearchParams) ([]models.SearchResult, int, error)
 	DeleteComment(ctx context.Context, id int64) error
+	GetPendingComments(ctx context.Context, queryParams models.QueryParams) ([]models.Comment, int, error)
+	ModerateComment(ctx context.Context, id int64, status string) (models.Comment, error)
+	LockComment(ctx context.Context, id int64) (models.Comment, error)
+	PinComment(ctx context.Context, id int64) (models.Comment, error)
+	UnpinComment(ctx context.Context, id int64) (models.Comment, error)
+	UnlockComment(ctx context.Context, id int64) (models.Comment, error)
+	MoveComment(ctx context.Context, id int64, parentID *int64) (models.Comment, error)
+	LockInactiveThreads(ctx context.Context, inactivity time.Duration) (int, error)
+	ReportComment(ctx context.Context, report models.Report, threshold int) (models.Comment, error)
+	GetReportedComments(ctx context.Context, queryParams models.QueryParams) ([]models.ReportedComment, int, error)
+	GetUserRole(ctx context.Context, subject string) (string, error)
+	CreateAPIKey(ctx context.Context, key models.APIKey, hash string) (models.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int64) error
+	UseAPIKey(ctx context.Context, hash string) (models.APIKey, error)
 }
 
 func NewStorage(logger logger.Logger, config config.Storage, db *dbpg.DB) Storage {
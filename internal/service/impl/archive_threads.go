@@ -0,0 +1,29 @@
+package impl
+
+import (
+	"context"
+	"time"
+)
+
+// ArchiveThreads locks threads that have been inactive for the configured
+// number of days and returns how many it locked.
+func (s *Service) ArchiveThreads(ctx context.Context) (int, error) {
+
+	days := s.config.Archive.InactiveDays
+	if days <= 0 {
+		return 0, nil
+	}
+
+	locked, err := s.storage.LockInactiveThreads(ctx, time.Duration(days)*24*time.Hour)
+	if err != nil {
+		s.logger.LogError("service — failed to archive threads", err, "layer", "service.impl")
+		return 0, err
+	}
+
+	if locked > 0 {
+		s.logger.LogInfo("service — archived inactive threads", "count", locked, "layer", "service.impl")
+	}
+
+	return locked, nil
+
+}
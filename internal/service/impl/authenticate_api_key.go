@@ -0,0 +1,30 @@
+package impl
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"errors"
+)
+
+// apiKeySubject namespaces the subjects of API keys so that a key can never
+// act as the user who happens to share its name. Live key names are unique,
+// so each subject belongs to one key at a time.
+const apiKeySubject = "apikey:"
+
+// AuthenticateAPIKey resolves a secret key to the identity of its integration.
+// Unknown and revoked keys are rejected with ErrUnauthorized.
+func (s *Service) AuthenticateAPIKey(ctx context.Context, secret string) (models.Identity, error) {
+
+	key, err := s.storage.UseAPIKey(ctx, hashAPIKey(secret))
+	if err != nil {
+		if errors.Is(err, errs.ErrAPIKeyNotFound) {
+			return models.Identity{}, errs.ErrUnauthorized
+		}
+		s.logger.LogError("service — failed to authenticate api key", err, "layer", "service.impl")
+		return models.Identity{}, err
+	}
+
+	return models.Identity{Subject: apiKeySubject + key.Name, Scopes: key.Scopes}, nil
+
+}
@@ -0,0 +1,26 @@
+package impl
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+)
+
+// authorToken signs the ID of a comment posted by an anonymous caller. The
+// token is returned once, at creation, and proves authorship when the
+// caller later edits or deletes the comment.
+func (s *Service) authorToken(id int64) string {
+	mac := hmac.New(sha256.New, []byte(s.config.AuthorSecret))
+	mac.Write([]byte("author." + strconv.FormatInt(id, 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// validAuthorToken reports whether token was issued for comment id. Tokens
+// are never accepted without a secret to check them against.
+func (s *Service) validAuthorToken(id int64, token string) bool {
+	if s.config.AuthorSecret == "" || token == "" {
+		return false
+	}
+	return hmac.Equal([]byte(token), []byte(s.authorToken(id)))
+}
@@ -0,0 +1,51 @@
+package impl
+
+import (
+	"Hermes/internal/auth"
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"errors"
+)
+
+// authorize loads the live comment id and checks that caller may modify it:
+// only its author or someone allowed to delete any comment can. Comments
+// that are not approved are out of reach of everyone but moderators. An
+// authenticated author is recognised by subject, an anonymous one by the
+// author token issued when the comment was created.
+func (s *Service) authorize(ctx context.Context, id int64, caller models.Identity) (models.Comment, error) {
+
+	comment, err := s.storage.GetComment(ctx, id)
+	if err != nil {
+		if errors.Is(err, errs.ErrCommentNotFound) {
+			return models.Comment{}, err
+		}
+		s.logger.LogError("service — failed to get comment", err, "id", id, "layer", "service.impl")
+		return models.Comment{}, err
+	}
+
+	if comment.DeletedAt != nil {
+		return models.Comment{}, errs.ErrCommentNotFound
+	}
+
+	if comment.Status != models.StatusApproved && !auth.Can(caller, auth.PermModerate) {
+		return models.Comment{}, errs.ErrCommentNotFound
+	}
+
+	if !s.canModify(caller, comment) {
+		return models.Comment{}, errs.ErrForbidden
+	}
+
+	return comment, nil
+
+}
+
+func (s *Service) canModify(caller models.Identity, comment models.Comment) bool {
+	if auth.Can(caller, auth.PermDeleteAny) {
+		return true
+	}
+	if caller.Subject != "" {
+		return caller.Subject == comment.Author
+	}
+	return s.validAuthorToken(comment.ID, caller.AuthorToken)
+}
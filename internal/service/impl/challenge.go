@@ -0,0 +1,121 @@
+package impl
+
+import (
+	"Hermes/internal/auth"
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	challengeVersion = "v1"
+	maxNonceLength   = 64
+)
+
+// checkProof verifies the proof of work required from anonymous callers
+// without spending it. It returns the challenge ID and expiry to pass to
+// spendChallenge, or an empty ID when no proof is required.
+func (s *Service) checkProof(ctx context.Context, proof models.Proof) (string, time.Time, error) {
+
+	if !s.config.Challenge.Enabled {
+		return "", time.Time{}, nil
+	}
+
+	if _, ok := auth.IdentityFromContext(ctx); ok {
+		return "", time.Time{}, nil
+	}
+
+	if proof.Challenge == "" || proof.Nonce == "" {
+		return "", time.Time{}, errs.ErrProofRequired
+	}
+
+	return s.verifyProof(proof, time.Now())
+
+}
+
+// spendChallenge marks a verified challenge as used so it cannot be
+// replayed. It runs only once the comment is about to be stored, so a
+// comment refused for other reasons can be retried with the same proof.
+func (s *Service) spendChallenge(ctx context.Context, id string, expiresAt time.Time) error {
+
+	if id == "" {
+		return nil
+	}
+
+	if err := s.storage.UseChallenge(ctx, id, expiresAt); err != nil {
+		if errors.Is(err, errs.ErrProofReused) {
+			return err
+		}
+		s.logger.LogError("service — failed to use challenge", err, "layer", "service.impl")
+		return err
+	}
+
+	return nil
+
+}
+
+// verifyProof checks the challenge's signature and expiry and that the
+// nonce solves it, returning the challenge ID and expiry.
+func (s *Service) verifyProof(proof models.Proof, now time.Time) (string, time.Time, error) {
+
+	parts := strings.Split(proof.Challenge, ".")
+	if len(parts) != 5 || parts[0] != challengeVersion || len(proof.Nonce) > maxNonceLength {
+		return "", time.Time{}, errs.ErrInvalidProof
+	}
+
+	id, payload, signature := parts[1], strings.Join(parts[:4], "."), parts[4]
+
+	if !hmac.Equal([]byte(signature), []byte(s.signChallenge(payload))) {
+		return "", time.Time{}, errs.ErrInvalidProof
+	}
+
+	difficulty, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return "", time.Time{}, errs.ErrInvalidProof
+	}
+
+	expires, err := strconv.ParseInt(parts[3], 10, 64)
+	if err != nil || now.Unix() > expires {
+		return "", time.Time{}, errs.ErrInvalidProof
+	}
+
+	sum := sha256.Sum256([]byte(proof.Challenge + ":" + proof.Nonce))
+	if leadingZeroBits(sum[:]) < difficulty {
+		return "", time.Time{}, errs.ErrInvalidProof
+	}
+
+	return id, time.Unix(expires, 0), nil
+
+}
+
+// newChallenge builds a signed challenge of the form
+// "v1.<id>.<difficulty>.<expires>.<signature>".
+func (s *Service) newChallenge(id string, difficulty int, expiresAt time.Time) string {
+	payload := strings.Join([]string{challengeVersion, id, strconv.Itoa(difficulty), strconv.FormatInt(expiresAt.Unix(), 10)}, ".")
+	return payload + "." + s.signChallenge(payload)
+}
+
+func (s *Service) signChallenge(payload string) string {
+	mac := hmac.New(sha256.New, []byte(s.config.Challenge.Secret))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func leadingZeroBits(sum []byte) int {
+	n := 0
+	for _, b := range sum {
+		if b != 0 {
+			return n + bits.LeadingZeros8(b)
+		}
+		n += 8
+	}
+	return n
+}
@@ -0,0 +1,22 @@
+package impl
+
+import (
+	"Hermes/internal/models"
+	"context"
+)
+
+func (s *Service) CountComments(ctx context.Context, params models.QueryParams) (int, error) {
+
+	if err := validateThreadKey(params.ThreadKey); err != nil {
+		return 0, err
+	}
+
+	total, err := s.storage.CountRootComments(ctx, params)
+	if err != nil {
+		s.logger.LogError("service — failed to count root comments", err, "layer", "service.impl")
+		return 0, err
+	}
+
+	return total, nil
+
+}
@@ -0,0 +1,71 @@
+package impl
+
+import (
+	"Hermes/internal/auth"
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+const (
+	apiKeyPrefix       = "hk_"
+	apiKeyBytes        = 32
+	apiKeyVisibleChars = len(apiKeyPrefix) + 8
+	maxKeyNameLength   = 255
+)
+
+// CreateAPIKey issues a new key named name with the given scopes on behalf of
+// caller. The secret key is returned only here; storage keeps its hash. The
+// name identifies the key's comments, so live keys can not share it.
+func (s *Service) CreateAPIKey(ctx context.Context, name string, scopes []string, caller models.Identity) (models.APIKey, string, error) {
+
+	name = strings.TrimSpace(name)
+	if name == "" || len(name) > maxKeyNameLength {
+		return models.APIKey{}, "", errs.ErrInvalidKeyName
+	}
+
+	for _, scope := range scopes {
+		if !auth.IsPermission(scope) {
+			return models.APIKey{}, "", errs.ErrInvalidScope
+		}
+	}
+
+	raw := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(raw); err != nil {
+		s.logger.LogError("service — failed to generate api key", err, "layer", "service.impl")
+		return models.APIKey{}, "", err
+	}
+	secret := apiKeyPrefix + hex.EncodeToString(raw)
+
+	key := models.APIKey{
+		Name:      name,
+		Prefix:    secret[:apiKeyVisibleChars],
+		Scopes:    scopes,
+		CreatedBy: caller.Subject,
+	}
+	if key.Scopes == nil {
+		key.Scopes = []string{}
+	}
+
+	created, err := s.storage.CreateAPIKey(ctx, key, hashAPIKey(secret))
+	if err != nil {
+		if errors.Is(err, errs.ErrKeyNameTaken) {
+			return models.APIKey{}, "", err
+		}
+		s.logger.LogError("service — failed to create api key", err, "layer", "service.impl")
+		return models.APIKey{}, "", err
+	}
+
+	return created, secret, nil
+
+}
+
+func hashAPIKey(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,7 +1,9 @@
 package impl
 
 import (
+	"Hermes/internal/auth"
 	"Hermes/internal/errs"
+	"Hermes/internal/filter"
 	"Hermes/internal/models"
 	"context"
 	"errors"
@@ -9,22 +11,82 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
-func (s *Service) CreateComment(ctx context.Context, comment models.Comment) (int64, error) {
+// CreateComment stores a new comment and returns its ID. Anonymous callers
+// also get an author token they need to edit or delete it later.
+func (s *Service) CreateComment(ctx context.Context, comment models.Comment, proof models.Proof) (int64, string, error) {
 
 	if err := validateComment(comment); err != nil {
-		return 0, err
+		return 0, "", err
+	}
+
+	if comment.ParentID != nil {
+		if err := s.checkParent(ctx, *comment.ParentID); err != nil {
+			return 0, "", err
+		}
+	}
+
+	challengeID, challengeExpiresAt, err := s.checkProof(ctx, proof)
+	if err != nil {
+		return 0, "", err
+	}
+
+	verdict, err := s.filterComment(ctx, comment)
+	if err != nil {
+		return 0, "", err
+	}
+
+	comment.Status = models.StatusApproved
+	if s.config.PreApproval || verdict == filter.Flag {
+		comment.Status = models.StatusPending
+	}
+
+	if err := s.spendChallenge(ctx, challengeID, challengeExpiresAt); err != nil {
+		return 0, "", err
 	}
 
 	id, err := s.storage.CreateComment(ctx, comment)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23503" { // foreign key violation
-			return 0, errs.ErrParentNotFound
+			return 0, "", errs.ErrParentNotFound
+		}
+		if errors.Is(err, errs.ErrParentNotFound) || errors.Is(err, errs.ErrThreadLocked) {
+			return 0, "", err
 		}
 		s.logger.LogError("service — failed to create comment", err, "id", id, "layer", "service.impl")
-		return 0, err
+		return 0, "", err
+	}
+
+	if _, ok := auth.IdentityFromContext(ctx); ok {
+		return id, "", nil
+	}
+
+	return id, s.authorToken(id), nil
+
+}
+
+// checkParent turns away replies to a missing, hidden or locked parent
+// before a proof of work is spent on them. Locks further up the thread are
+// enforced by storage when the reply is inserted.
+func (s *Service) checkParent(ctx context.Context, parentID int64) error {
+
+	parent, err := s.storage.GetComment(ctx, parentID)
+	if err != nil {
+		if errors.Is(err, errs.ErrCommentNotFound) {
+			return errs.ErrParentNotFound
+		}
+		s.logger.LogError("service — failed to get parent comment", err, "id", parentID, "layer", "service.impl")
+		return err
+	}
+
+	if parent.Status != models.StatusApproved {
+		return errs.ErrParentNotFound
+	}
+
+	if parent.LockedAt != nil {
+		return errs.ErrThreadLocked
 	}
 
-	return id, nil
+	return nil
 
 }
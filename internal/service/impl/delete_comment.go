@@ -2,11 +2,17 @@ package impl
 
 import (
 	"Hermes/internal/errs"
+	"Hermes/internal/models"
 	"context"
 	"errors"
 )
 
-func (s *Service) DeleteComment(ctx context.Context, id int64) error {
+func (s *Service) DeleteComment(ctx context.Context, id int64, caller models.Identity) error {
+
+	if _, err := s.authorize(ctx, id, caller); err != nil {
+		return err
+	}
+
 	if err := s.storage.DeleteComment(ctx, id); err != nil {
 		if errors.Is(err, errs.ErrCommentNotFound) {
 			return err
@@ -14,5 +20,7 @@ func (s *Service) DeleteComment(ctx context.Context, id int64) error {
 		s.logger.LogError("service — failed to delete comment", err, "id", id, "layer", "service.impl")
 		return err
 	}
+
 	return nil
+
 }
@@ -0,0 +1,35 @@
+package impl
+
+import (
+	"Hermes/internal/filter"
+	"Hermes/internal/models"
+	"context"
+)
+
+// filterComment runs the content filters in order. The first rejection
+// stops the pipeline and its reason is returned as the error; otherwise the
+// comment is flagged if any filter flagged it.
+func (s *Service) filterComment(ctx context.Context, comment models.Comment) (filter.Verdict, error) {
+
+	verdict := filter.Allow
+
+	for _, f := range s.filters {
+
+		result, err := f.Check(ctx, comment)
+		if err != nil {
+			s.logger.LogError("service — failed to filter comment", err, "layer", "service.impl")
+			return filter.Allow, err
+		}
+
+		switch result.Verdict {
+		case filter.Reject:
+			return filter.Reject, result.Reason
+		case filter.Flag:
+			verdict = filter.Flag
+		}
+
+	}
+
+	return verdict, nil
+
+}
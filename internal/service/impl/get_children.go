@@ -0,0 +1,29 @@
+package impl
+
+import (
+	"Hermes/internal/models"
+	"context"
+)
+
+// GetChildren returns a page of replies under a public comment. Like
+// GetComment, it reports pending, rejected and unknown parents as not found.
+func (s *Service) GetChildren(ctx context.Context, parentID int64, params models.QueryParams) ([]models.Comment, int, error) {
+
+	if _, err := s.GetComment(ctx, parentID); err != nil {
+		return nil, 0, err
+	}
+
+	children, total, err := s.storage.GetChildren(ctx, parentID, params)
+	if err != nil {
+		s.logger.LogError("service — failed to get children", err, "id", parentID, "layer", "service.impl")
+		return nil, 0, err
+	}
+
+	trees, err := s.loadTrees(ctx, children, params.Sort, params.TreeLimits)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return trees, total, nil
+
+}
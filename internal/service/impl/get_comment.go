@@ -0,0 +1,49 @@
+package impl
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"errors"
+)
+
+func (s *Service) GetComment(ctx context.Context, id int64) (models.Comment, error) {
+
+	comment, err := s.storage.GetComment(ctx, id)
+	if err != nil {
+		if errors.Is(err, errs.ErrCommentNotFound) {
+			return models.Comment{}, err
+		}
+		s.logger.LogError("service — failed to get comment", err, "id", id, "layer", "service.impl")
+		return models.Comment{}, err
+	}
+
+	if comment.Status != models.StatusApproved {
+		return models.Comment{}, errs.ErrCommentNotFound
+	}
+
+	if comment.DeletedAt != nil {
+		tombstone(&comment)
+	}
+
+	return comment, nil
+
+}
+
+func (s *Service) GetAncestors(ctx context.Context, id int64) ([]models.Comment, error) {
+
+	ancestors, err := s.storage.GetAncestors(ctx, id)
+	if err != nil {
+		s.logger.LogError("service — failed to get ancestors", err, "id", id, "layer", "service.impl")
+		return nil, err
+	}
+
+	for i := range ancestors {
+		if ancestors[i].DeletedAt != nil {
+			tombstone(&ancestors[i])
+		}
+	}
+
+	return ancestors, nil
+
+}
@@ -5,29 +5,69 @@ import (
 	"context"
 )
 
+const deletedPlaceholder = "[deleted]"
+
 func (s *Service) GetComments(ctx context.Context, params models.QueryParams) ([]models.Comment, error) {
 
+	if err := validateThreadKey(params.ThreadKey); err != nil {
+		return nil, err
+	}
+
 	roots, err := s.storage.GetRootComments(ctx, params)
 	if err != nil {
 		s.logger.LogError("service — failed to get root comments", err, "layer", "service.impl")
 		return nil, err
 	}
 
-	var result []models.Comment
+	return s.loadTrees(ctx, roots, params.Sort, params.TreeLimits)
 
-	for _, root := range roots {
+}
 
-		flat, err := s.storage.GetCommentTree(ctx, root.ID)
-		if err != nil {
-			s.logger.LogError("service — failed to get comment tree", err, "layer", "service.impl")
-			return nil, err
-		}
+// loadTrees fetches the subtrees of roots and their reaction counts in one
+// storage call each and returns them assembled, in the same order as roots.
+func (s *Service) loadTrees(ctx context.Context, roots []models.Comment, sort string, limits models.TreeLimits) ([]models.Comment, error) {
 
-		tree := buildTree(flat)
-		if len(tree) > 0 {
-			result = append(result, *tree[0])
-		}
+	if len(roots) == 0 {
+		return nil, nil
+	}
+
+	rootIDs := make([]int64, len(roots))
+	for i, root := range roots {
+		rootIDs[i] = root.ID
+	}
 
+	flat, err := s.storage.GetCommentTrees(ctx, rootIDs, sort, limits)
+	if err != nil {
+		s.logger.LogError("service — failed to get comment trees", err, "layer", "service.impl")
+		return nil, err
+	}
+
+	ids := make([]int64, len(flat))
+	for i, comment := range flat {
+		ids[i] = comment.ID
+	}
+
+	reactions, err := s.storage.GetReactions(ctx, ids)
+	if err != nil {
+		s.logger.LogError("service — failed to get reactions", err, "layer", "service.impl")
+		return nil, err
+	}
+
+	for i := range flat {
+		flat[i].Reactions = reactions[flat[i].ID]
+	}
+
+	trees := make(map[int64]*models.Comment, len(roots))
+	for _, tree := range buildTree(flat) {
+		trees[tree.ID] = tree
+	}
+
+	var result []models.Comment
+
+	for _, root := range roots {
+		if tree, ok := trees[root.ID]; ok {
+			result = append(result, *tree)
+		}
 	}
 
 	return result, nil
@@ -40,6 +80,9 @@ func buildTree(comments []models.Comment) []*models.Comment {
 	var roots []*models.Comment
 
 	for i := range comments {
+		if comments[i].DeletedAt != nil {
+			tombstone(&comments[i])
+		}
 		hm[comments[i].ID] = &comments[i]
 	}
 
@@ -61,6 +104,17 @@ func buildTree(comments []models.Comment) []*models.Comment {
 		}
 	}
 
+	for _, node := range hm {
+		node.HasMoreChildren = node.ChildrenCount > len(node.Children)
+	}
+
 	return roots
 
 }
+
+// tombstone hides the content of a soft-deleted comment while keeping
+// the node itself, so its replies stay attached to the tree.
+func tombstone(comment *models.Comment) {
+	comment.Content = deletedPlaceholder
+	comment.Author = deletedPlaceholder
+}
@@ -0,0 +1,18 @@
+package impl
+
+import (
+	"Hermes/internal/models"
+	"context"
+)
+
+func (s *Service) GetPendingComments(ctx context.Context, params models.QueryParams) ([]models.Comment, int, error) {
+
+	comments, total, err := s.storage.GetPendingComments(ctx, params)
+	if err != nil {
+		s.logger.LogError("service — failed to get pending comments", err, "layer", "service.impl")
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+
+}
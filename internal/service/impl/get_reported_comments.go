@@ -0,0 +1,18 @@
+package impl
+
+import (
+	"Hermes/internal/models"
+	"context"
+)
+
+func (s *Service) GetReportedComments(ctx context.Context, params models.QueryParams) ([]models.ReportedComment, int, error) {
+
+	comments, total, err := s.storage.GetReportedComments(ctx, params)
+	if err != nil {
+		s.logger.LogError("service — failed to get reported comments", err, "layer", "service.impl")
+		return nil, 0, err
+	}
+
+	return comments, total, nil
+
+}
@@ -0,0 +1,23 @@
+package impl
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"errors"
+)
+
+func (s *Service) GetRevisions(ctx context.Context, commentID int64) ([]models.Revision, error) {
+
+	revisions, err := s.storage.GetRevisions(ctx, commentID)
+	if err != nil {
+		if errors.Is(err, errs.ErrCommentNotFound) {
+			return nil, err
+		}
+		s.logger.LogError("service — failed to get revisions", err, "id", commentID, "layer", "service.impl")
+		return nil, err
+	}
+
+	return revisions, nil
+
+}
@@ -1,15 +1,19 @@
 package impl
 
 import (
+	"Hermes/internal/config"
+	"Hermes/internal/filter"
 	"Hermes/internal/logger"
 	"Hermes/internal/repository"
 )
 
 type Service struct {
 	logger  logger.Logger
+	config  config.Comments
 	storage repository.Storage
+	filters []filter.ContentFilter
 }
 
-func NewService(logger logger.Logger, storage repository.Storage) *Service {
-	return &Service{logger: logger, storage: storage}
+func NewService(logger logger.Logger, config config.Comments, storage repository.Storage, filters []filter.ContentFilter) *Service {
+	return &Service{logger: logger, config: config, storage: storage, filters: filters}
 }
@@ -1,13 +1,20 @@
 package impl
 
 import (
+	"Hermes/internal/auth"
+	"Hermes/internal/config"
 	"Hermes/internal/errs"
+	"Hermes/internal/filter"
 	mockLogger "Hermes/internal/logger/mocks"
 	"Hermes/internal/models"
 	mockStorage "Hermes/internal/repository/mocks"
 	"context"
+	"crypto/sha256"
 	"errors"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/require"
@@ -22,11 +29,17 @@ func TestNewService(t *testing.T) {
 	mockLogger := mockLogger.NewMockLogger(controller)
 	mockStorage := mockStorage.NewMockStorage(controller)
 
-	svc := NewService(mockLogger, mockStorage)
+	cfg := config.Comments{Reactions: []string{"👍"}}
+
+	filters := []filter.ContentFilter{filter.NewLinks(filter.Flag, 1)}
+
+	svc := NewService(mockLogger, cfg, mockStorage, filters)
 
 	require.NotNil(t, svc)
 	require.Equal(t, mockLogger, svc.logger)
+	require.Equal(t, cfg, svc.config)
 	require.Equal(t, mockStorage, svc.storage)
+	require.Equal(t, filters, svc.filters)
 
 }
 
@@ -53,6 +66,19 @@ func TestValidateComment(t *testing.T) {
 		require.ErrorIs(t, err, errs.ErrEmptyAuthor)
 	})
 
+	t.Run("thread key too long", func(t *testing.T) {
+		c := validComment
+		c.ThreadKey = strings.Repeat("k", maxThreadKeyLength+1)
+		err := validateComment(c)
+		require.ErrorIs(t, err, errs.ErrInvalidThreadKey)
+	})
+
+	t.Run("multibyte thread key at the limit", func(t *testing.T) {
+		c := validComment
+		c.ThreadKey = strings.Repeat("ж", maxThreadKeyLength)
+		require.NoError(t, validateComment(c))
+	})
+
 }
 
 func TestService_CreateComment(t *testing.T) {
@@ -65,13 +91,13 @@ func TestService_CreateComment(t *testing.T) {
 	mockLogger := mockLogger.NewMockLogger(controller)
 	mockStorage := mockStorage.NewMockStorage(controller)
 
-	svc := &Service{logger: mockLogger, storage: mockStorage}
-	comment := models.Comment{Content: "hello", Author: "user"}
+	svc := &Service{logger: mockLogger, config: config.Comments{AuthorSecret: "secret"}, storage: mockStorage}
+	comment := models.Comment{Content: "hello", Author: "user", Status: models.StatusApproved}
 
 	t.Run("validateComment error", func(t *testing.T) {
 		invalid := comment
 		invalid.Content = ""
-		id, err := svc.CreateComment(ctx, invalid)
+		id, _, err := svc.CreateComment(ctx, invalid, models.Proof{})
 		require.Equal(t, int64(0), id)
 		require.Error(t, err)
 	})
@@ -79,15 +105,25 @@ func TestService_CreateComment(t *testing.T) {
 	t.Run("storage.CreateComment succeeds", func(t *testing.T) {
 		expectedID := int64(123)
 		mockStorage.EXPECT().CreateComment(ctx, comment).Return(expectedID, nil)
-		id, err := svc.CreateComment(ctx, comment)
+		id, token, err := svc.CreateComment(ctx, comment, models.Proof{})
 		require.NoError(t, err)
 		require.Equal(t, expectedID, id)
+		require.True(t, svc.validAuthorToken(id, token))
+		require.False(t, svc.validAuthorToken(id+1, token))
+	})
+
+	t.Run("authenticated callers get no author token", func(t *testing.T) {
+		authed := auth.WithIdentity(ctx, models.Identity{Subject: "user"})
+		mockStorage.EXPECT().CreateComment(authed, comment).Return(int64(124), nil)
+		_, token, err := svc.CreateComment(authed, comment, models.Proof{})
+		require.NoError(t, err)
+		require.Empty(t, token)
 	})
 
 	t.Run("storage.CreateComment foreign key violation", func(t *testing.T) {
 		pgErr := &pgconn.PgError{Code: "23503"}
 		mockStorage.EXPECT().CreateComment(ctx, comment).Return(int64(0), pgErr)
-		id, err := svc.CreateComment(ctx, comment)
+		id, _, err := svc.CreateComment(ctx, comment, models.Proof{})
 		require.Equal(t, int64(0), id)
 		require.ErrorIs(t, err, errs.ErrParentNotFound)
 	})
@@ -96,145 +132,1400 @@ func TestService_CreateComment(t *testing.T) {
 		dbErr := errors.New("db down")
 		mockStorage.EXPECT().CreateComment(ctx, comment).Return(int64(0), dbErr)
 		mockLogger.EXPECT().LogError("service — failed to create comment", dbErr, "id", int64(0), "layer", "service.impl")
-		id, err := svc.CreateComment(ctx, comment)
+		id, _, err := svc.CreateComment(ctx, comment, models.Proof{})
 		require.Equal(t, int64(0), id)
 		require.EqualError(t, err, "db down")
 	})
 
+	t.Run("pre-approval holds new comments", func(t *testing.T) {
+		moderated := &Service{logger: mockLogger, config: config.Comments{PreApproval: true}, storage: mockStorage}
+		pending := comment
+		pending.Status = models.StatusPending
+		mockStorage.EXPECT().CreateComment(ctx, pending).Return(int64(9), nil)
+		id, _, err := moderated.CreateComment(ctx, models.Comment{Content: "hello", Author: "user"}, models.Proof{})
+		require.NoError(t, err)
+		require.Equal(t, int64(9), id)
+	})
+
 }
 
-func TestService_DeleteComment(t *testing.T) {
+func TestService_CreateComment_Filters(t *testing.T) {
 
 	ctx := context.Background()
-	commentID := int64(123)
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+
+	mockLogger := mockLogger.NewMockLogger(controller)
+	mockStorage := mockStorage.NewMockStorage(controller)
+
+	filters, err := filter.New(config.Filters{
+		BannedWords:   config.BannedWordsFilter{Action: "reject", Words: []string{"Casino"}},
+		Links:         config.LimitFilter{Action: "flag", Max: 1},
+		RepeatedChars: config.LimitFilter{Action: "reject", Max: 3},
+		Duplicates:    config.DuplicatesFilter{Action: "reject", Window: 5 * time.Minute},
+	}, mockStorage)
+	require.NoError(t, err)
+	require.Len(t, filters, 4)
+
+	svc := &Service{logger: mockLogger, storage: mockStorage, filters: filters}
+
+	t.Run("banned word", func(t *testing.T) {
+		_, _, err := svc.CreateComment(ctx, models.Comment{Content: "Best CASINO in town", Author: "spammer"}, models.Proof{})
+		require.ErrorIs(t, err, errs.ErrBannedWords)
+	})
+
+	t.Run("banned word inside another word", func(t *testing.T) {
+		mockStorage.EXPECT().HasRecentDuplicate(ctx, "user", "casinos are loud", 5*time.Minute).Return(false, nil)
+		mockStorage.EXPECT().CreateComment(ctx, models.Comment{Content: "casinos are loud", Author: "user", Status: models.StatusApproved}).Return(int64(1), nil)
+		_, _, err := svc.CreateComment(ctx, models.Comment{Content: "casinos are loud", Author: "user"}, models.Proof{})
+		require.NoError(t, err)
+	})
+
+	t.Run("repeated characters", func(t *testing.T) {
+		_, _, err := svc.CreateComment(ctx, models.Comment{Content: "wow!!!!", Author: "user"}, models.Proof{})
+		require.ErrorIs(t, err, errs.ErrRepeatedChars)
+	})
+
+	t.Run("too many links are held for moderation", func(t *testing.T) {
+		content := "see https://a.example and www.b.example"
+		mockStorage.EXPECT().HasRecentDuplicate(ctx, "user", content, 5*time.Minute).Return(false, nil)
+		mockStorage.EXPECT().CreateComment(ctx, models.Comment{Content: content, Author: "user", Status: models.StatusPending}).Return(int64(2), nil)
+		id, _, err := svc.CreateComment(ctx, models.Comment{Content: content, Author: "user"}, models.Proof{})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), id)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		mockStorage.EXPECT().HasRecentDuplicate(ctx, "user", "hello", 5*time.Minute).Return(true, nil)
+		_, _, err := svc.CreateComment(ctx, models.Comment{Content: " hello ", Author: "user"}, models.Proof{})
+		require.ErrorIs(t, err, errs.ErrDuplicateComment)
+	})
+
+	t.Run("filter fails", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().HasRecentDuplicate(ctx, "user", "hello", 5*time.Minute).Return(false, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to filter comment", gomock.Any(), "layer", "service.impl")
+		_, _, err := svc.CreateComment(ctx, models.Comment{Content: "hello", Author: "user"}, models.Proof{})
+		require.ErrorIs(t, err, dbErr)
+	})
+
+	t.Run("unknown action", func(t *testing.T) {
+		_, err := filter.New(config.Filters{Links: config.LimitFilter{Action: "drop", Max: 1}}, mockStorage)
+		require.Error(t, err)
+	})
+
+	t.Run("disabled filters", func(t *testing.T) {
+		filters, err := filter.New(config.Filters{
+			BannedWords: config.BannedWordsFilter{Action: "reject"},
+			Links:       config.LimitFilter{Action: "allow", Max: 1},
+		}, mockStorage)
+		require.NoError(t, err)
+		require.Empty(t, filters)
+	})
+
+}
+
+func TestService_CreateComment_Replies(t *testing.T) {
 
+	ctx := context.Background()
 	controller := gomock.NewController(t)
+
 	defer controller.Finish()
 
 	mockLogger := mockLogger.NewMockLogger(controller)
 	mockStorage := mockStorage.NewMockStorage(controller)
 
 	svc := &Service{logger: mockLogger, storage: mockStorage}
+	rootID, parentID := int64(1), int64(2)
+	lockedAt := time.Now()
+	reply := models.Comment{ParentID: &parentID, Content: "hello", Author: "user"}
 
-	t.Run("storage.DeleteComment succeeds", func(t *testing.T) {
-		mockStorage.EXPECT().DeleteComment(ctx, commentID).Return(nil)
-		err := svc.DeleteComment(ctx, commentID)
+	t.Run("parent not found", func(t *testing.T) {
+		mockStorage.EXPECT().GetComment(ctx, parentID).Return(models.Comment{}, errs.ErrCommentNotFound)
+		_, _, err := svc.CreateComment(ctx, reply, models.Proof{})
+		require.ErrorIs(t, err, errs.ErrParentNotFound)
+	})
+
+	t.Run("parent not public", func(t *testing.T) {
+		mockStorage.EXPECT().GetComment(ctx, parentID).Return(models.Comment{ID: parentID, Status: models.StatusPending}, nil)
+		_, _, err := svc.CreateComment(ctx, reply, models.Proof{})
+		require.ErrorIs(t, err, errs.ErrParentNotFound)
+	})
+
+	t.Run("parent locked", func(t *testing.T) {
+		mockStorage.EXPECT().GetComment(ctx, parentID).Return(models.Comment{ID: parentID, Status: models.StatusApproved, LockedAt: &lockedAt}, nil)
+		_, _, err := svc.CreateComment(ctx, reply, models.Proof{})
+		require.ErrorIs(t, err, errs.ErrThreadLocked)
+	})
+
+	t.Run("ancestor locked", func(t *testing.T) {
+		approved := reply
+		approved.Status = models.StatusApproved
+		mockStorage.EXPECT().GetComment(ctx, parentID).Return(models.Comment{ID: parentID, ParentID: &rootID, Status: models.StatusApproved}, nil)
+		mockStorage.EXPECT().CreateComment(ctx, approved).Return(int64(0), errs.ErrThreadLocked)
+		_, _, err := svc.CreateComment(ctx, reply, models.Proof{})
+		require.ErrorIs(t, err, errs.ErrThreadLocked)
+	})
+
+	t.Run("parent hidden after the check", func(t *testing.T) {
+		approved := reply
+		approved.Status = models.StatusApproved
+		mockStorage.EXPECT().GetComment(ctx, parentID).Return(models.Comment{ID: parentID, ParentID: &rootID, Status: models.StatusApproved}, nil)
+		mockStorage.EXPECT().CreateComment(ctx, approved).Return(int64(0), errs.ErrParentNotFound)
+		_, _, err := svc.CreateComment(ctx, reply, models.Proof{})
+		require.ErrorIs(t, err, errs.ErrParentNotFound)
+	})
+
+	t.Run("open thread", func(t *testing.T) {
+		mockStorage.EXPECT().GetComment(ctx, parentID).Return(models.Comment{ID: parentID, ParentID: &rootID, Status: models.StatusApproved}, nil)
+		approved := reply
+		approved.Status = models.StatusApproved
+		mockStorage.EXPECT().CreateComment(ctx, approved).Return(int64(3), nil)
+		id, _, err := svc.CreateComment(ctx, reply, models.Proof{})
 		require.NoError(t, err)
+		require.Equal(t, int64(3), id)
 	})
 
-	t.Run("storage.DeleteComment ErrCommentNotFound", func(t *testing.T) {
-		mockStorage.EXPECT().DeleteComment(ctx, commentID).Return(errs.ErrCommentNotFound)
-		err := svc.DeleteComment(ctx, commentID)
+	t.Run("GetComment fails", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().GetComment(ctx, parentID).Return(models.Comment{}, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to get parent comment", dbErr, "id", parentID, "layer", "service.impl")
+		_, _, err := svc.CreateComment(ctx, reply, models.Proof{})
+		require.EqualError(t, err, "db down")
+	})
+
+}
+
+func TestService_LockComment(t *testing.T) {
+
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+
+	mockLogger := mockLogger.NewMockLogger(controller)
+	mockStorage := mockStorage.NewMockStorage(controller)
+
+	svc := &Service{logger: mockLogger, config: config.Comments{Archive: config.Archive{InactiveDays: 30}}, storage: mockStorage}
+	lockedAt := time.Now()
+
+	t.Run("lock", func(t *testing.T) {
+		mockStorage.EXPECT().LockComment(ctx, int64(1)).Return(models.Comment{ID: 1, LockedAt: &lockedAt}, nil)
+		comment, err := svc.LockComment(ctx, 1)
+		require.NoError(t, err)
+		require.NotNil(t, comment.LockedAt)
+	})
+
+	t.Run("unlock missing comment", func(t *testing.T) {
+		mockStorage.EXPECT().UnlockComment(ctx, int64(2)).Return(models.Comment{}, errs.ErrCommentNotFound)
+		_, err := svc.UnlockComment(ctx, 2)
 		require.ErrorIs(t, err, errs.ErrCommentNotFound)
 	})
 
-	t.Run("storage.DeleteComment generic error", func(t *testing.T) {
+	t.Run("lock fails", func(t *testing.T) {
 		dbErr := errors.New("db down")
-		mockStorage.EXPECT().DeleteComment(ctx, commentID).Return(dbErr)
-		mockLogger.EXPECT().LogError("service — failed to delete comment", dbErr, "id", commentID, "layer", "service.impl")
-		err := svc.DeleteComment(ctx, commentID)
+		mockStorage.EXPECT().LockComment(ctx, int64(3)).Return(models.Comment{}, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to lock comment", dbErr, "id", int64(3), "layer", "service.impl")
+		_, err := svc.LockComment(ctx, 3)
+		require.EqualError(t, err, "db down")
+	})
+
+	t.Run("pin", func(t *testing.T) {
+		mockStorage.EXPECT().GetComment(ctx, int64(4)).Return(models.Comment{ID: 4}, nil)
+		mockStorage.EXPECT().PinComment(ctx, int64(4)).Return(models.Comment{ID: 4, Pinned: true}, nil)
+		comment, err := svc.PinComment(ctx, 4)
+		require.NoError(t, err)
+		require.True(t, comment.Pinned)
+	})
+
+	t.Run("pin a reply", func(t *testing.T) {
+		parentID := int64(4)
+		mockStorage.EXPECT().GetComment(ctx, int64(5)).Return(models.Comment{ID: 5, ParentID: &parentID}, nil)
+		_, err := svc.PinComment(ctx, 5)
+		require.ErrorIs(t, err, errs.ErrNotRootComment)
+	})
+
+	t.Run("unpin missing comment", func(t *testing.T) {
+		mockStorage.EXPECT().GetComment(ctx, int64(6)).Return(models.Comment{}, errs.ErrCommentNotFound)
+		_, err := svc.UnpinComment(ctx, 6)
+		require.ErrorIs(t, err, errs.ErrCommentNotFound)
+	})
+
+	t.Run("unpin fails", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().GetComment(ctx, int64(4)).Return(models.Comment{ID: 4}, nil)
+		mockStorage.EXPECT().UnpinComment(ctx, int64(4)).Return(models.Comment{}, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to unpin comment", dbErr, "id", int64(4), "layer", "service.impl")
+		_, err := svc.UnpinComment(ctx, 4)
+		require.EqualError(t, err, "db down")
+	})
+
+	t.Run("archive", func(t *testing.T) {
+		mockStorage.EXPECT().LockInactiveThreads(ctx, 30*24*time.Hour).Return(2, nil)
+		mockLogger.EXPECT().LogInfo("service — archived inactive threads", "count", 2, "layer", "service.impl")
+		locked, err := svc.ArchiveThreads(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 2, locked)
+	})
+
+	t.Run("archive fails", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().LockInactiveThreads(ctx, 30*24*time.Hour).Return(0, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to archive threads", dbErr, "layer", "service.impl")
+		_, err := svc.ArchiveThreads(ctx)
 		require.EqualError(t, err, "db down")
 	})
+
+	t.Run("archive disabled", func(t *testing.T) {
+		locked, err := (&Service{logger: mockLogger, storage: mockStorage}).ArchiveThreads(ctx)
+		require.NoError(t, err)
+		require.Zero(t, locked)
+	})
+
 }
 
-func TestService_GetComments(t *testing.T) {
+func TestService_MoveComment(t *testing.T) {
 
 	ctx := context.Background()
-	params := models.QueryParams{}
-
 	controller := gomock.NewController(t)
+
 	defer controller.Finish()
 
 	mockLogger := mockLogger.NewMockLogger(controller)
 	mockStorage := mockStorage.NewMockStorage(controller)
 
 	svc := &Service{logger: mockLogger, storage: mockStorage}
+	parentID := int64(2)
 
-	t.Run("GetRootComments fails", func(t *testing.T) {
+	t.Run("move", func(t *testing.T) {
+		mockStorage.EXPECT().MoveComment(ctx, int64(1), &parentID).Return(models.Comment{ID: 1, ParentID: &parentID}, nil)
+		comment, err := svc.MoveComment(ctx, 1, &parentID)
+		require.NoError(t, err)
+		require.Equal(t, &parentID, comment.ParentID)
+	})
+
+	t.Run("promote to root", func(t *testing.T) {
+		mockStorage.EXPECT().MoveComment(ctx, int64(1), (*int64)(nil)).Return(models.Comment{ID: 1}, nil)
+		comment, err := svc.MoveComment(ctx, 1, nil)
+		require.NoError(t, err)
+		require.Nil(t, comment.ParentID)
+	})
+
+	t.Run("under itself", func(t *testing.T) {
+		self := int64(1)
+		_, err := svc.MoveComment(ctx, 1, &self)
+		require.ErrorIs(t, err, errs.ErrInvalidMove)
+	})
+
+	t.Run("invalid parent id", func(t *testing.T) {
+		zero := int64(0)
+		_, err := svc.MoveComment(ctx, 1, &zero)
+		require.ErrorIs(t, err, errs.ErrInvalidParentID)
+	})
+
+	t.Run("under a reply", func(t *testing.T) {
+		mockStorage.EXPECT().MoveComment(ctx, int64(1), &parentID).Return(models.Comment{}, errs.ErrInvalidMove)
+		_, err := svc.MoveComment(ctx, 1, &parentID)
+		require.ErrorIs(t, err, errs.ErrInvalidMove)
+	})
+
+	t.Run("missing parent", func(t *testing.T) {
+		mockStorage.EXPECT().MoveComment(ctx, int64(1), &parentID).Return(models.Comment{}, errs.ErrParentNotFound)
+		_, err := svc.MoveComment(ctx, 1, &parentID)
+		require.ErrorIs(t, err, errs.ErrParentNotFound)
+	})
+
+	t.Run("storage error", func(t *testing.T) {
 		dbErr := errors.New("db down")
-		mockStorage.EXPECT().GetRootComments(ctx, params).Return(nil, dbErr)
-		mockLogger.EXPECT().LogError("service — failed to get root comments", dbErr, "layer", "service.impl")
-		comments, err := svc.GetComments(ctx, params)
-		require.Nil(t, comments)
+		mockStorage.EXPECT().MoveComment(ctx, int64(1), &parentID).Return(models.Comment{}, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to move comment", dbErr, "id", int64(1), "layer", "service.impl")
+		_, err := svc.MoveComment(ctx, 1, &parentID)
 		require.EqualError(t, err, "db down")
 	})
 
-	t.Run("GetCommentTree fails for a root", func(t *testing.T) {
-		roots := []models.Comment{{ID: 1}}
-		mockStorage.EXPECT().GetRootComments(ctx, params).Return(roots, nil)
+}
+
+func TestService_Challenge(t *testing.T) {
+
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+
+	mockLogger := mockLogger.NewMockLogger(controller)
+	mockStorage := mockStorage.NewMockStorage(controller)
+
+	cfg := config.Comments{Challenge: config.Challenge{
+		Enabled:       true,
+		Secret:        "secret",
+		TTL:           time.Minute,
+		MinDifficulty: 4,
+		MaxDifficulty: 6,
+		RateWindow:    10 * time.Minute,
+		RateStep:      10,
+	}}
+	svc := &Service{logger: mockLogger, config: cfg, storage: mockStorage}
+	comment := models.Comment{Content: "hello", Author: "anon"}
+	approved := models.Comment{Content: "hello", Author: "anon", Status: models.StatusApproved}
+
+	solve := func(challenge string, difficulty int) models.Proof {
+		for i := 0; ; i++ {
+			nonce := strconv.Itoa(i)
+			sum := sha256.Sum256([]byte(challenge + ":" + nonce))
+			if leadingZeroBits(sum[:]) >= difficulty {
+				return models.Proof{Challenge: challenge, Nonce: nonce}
+			}
+		}
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		open := &Service{logger: mockLogger, storage: mockStorage}
+		challenge, err := open.IssueChallenge(ctx)
+		require.NoError(t, err)
+		require.False(t, challenge.Required)
+		mockStorage.EXPECT().CreateComment(ctx, approved).Return(int64(1), nil)
+		_, _, err = open.CreateComment(ctx, comment, models.Proof{})
+		require.NoError(t, err)
+	})
+
+	t.Run("difficulty follows the posting rate", func(t *testing.T) {
+		for recent, want := range map[int]int{0: 4, 19: 5, 25: 6, 500: 6} {
+			mockStorage.EXPECT().CountRecentComments(ctx, 10*time.Minute).Return(recent, nil)
+			challenge, err := svc.IssueChallenge(ctx)
+			require.NoError(t, err)
+			require.True(t, challenge.Required)
+			require.Equal(t, want, challenge.Difficulty)
+		}
+	})
+
+	t.Run("solved challenge is spent once", func(t *testing.T) {
+		mockStorage.EXPECT().CountRecentComments(ctx, 10*time.Minute).Return(25, nil)
+		challenge, err := svc.IssueChallenge(ctx)
+		require.NoError(t, err)
+		proof := solve(challenge.Challenge, challenge.Difficulty)
+		id := strings.Split(challenge.Challenge, ".")[1]
+
+		mockStorage.EXPECT().UseChallenge(ctx, id, challenge.ExpiresAt).Return(nil)
+		mockStorage.EXPECT().CreateComment(ctx, approved).Return(int64(2), nil)
+		created, _, err := svc.CreateComment(ctx, comment, proof)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), created)
+
+		mockStorage.EXPECT().UseChallenge(ctx, id, challenge.ExpiresAt).Return(errs.ErrProofReused)
+		_, _, err = svc.CreateComment(ctx, comment, proof)
+		require.ErrorIs(t, err, errs.ErrProofReused)
+	})
+
+	t.Run("missing proof", func(t *testing.T) {
+		_, _, err := svc.CreateComment(ctx, comment, models.Proof{})
+		require.ErrorIs(t, err, errs.ErrProofRequired)
+	})
+
+	t.Run("authenticated callers need no proof", func(t *testing.T) {
+		authed := auth.WithIdentity(ctx, models.Identity{Subject: "anon"})
+		mockStorage.EXPECT().CreateComment(authed, approved).Return(int64(3), nil)
+		_, _, err := svc.CreateComment(authed, comment, models.Proof{})
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid proofs", func(t *testing.T) {
+		future := time.Now().Add(time.Minute)
+		valid := svc.newChallenge("abc", 4, future)
+		tampered := strings.Replace(valid, ".4.", ".0.", 1)
+		forged := (&Service{config: config.Comments{Challenge: config.Challenge{Secret: "other"}}}).newChallenge("abc", 0, future)
+		expired := svc.newChallenge("abc", 0, time.Now().Add(-time.Second))
+		unsolvable := svc.newChallenge("abc", 257, future)
+
+		for name, proof := range map[string]models.Proof{
+			"malformed":  {Challenge: "v1.abc", Nonce: "1"},
+			"tampered":   solve(tampered, 0),
+			"forged":     solve(forged, 0),
+			"expired":    solve(expired, 0),
+			"unsolved":   {Challenge: unsolvable, Nonce: "1"},
+			"long nonce": {Challenge: valid, Nonce: strings.Repeat("1", maxNonceLength+1)},
+		} {
+			_, _, err := svc.CreateComment(ctx, comment, proof)
+			require.ErrorIs(t, err, errs.ErrInvalidProof, name)
+		}
+	})
+
+	t.Run("use challenge fails", func(t *testing.T) {
+		challenge := svc.newChallenge("abc", 0, time.Now().Add(time.Minute))
 		dbErr := errors.New("db down")
-		mockStorage.EXPECT().GetCommentTree(ctx, int64(1)).Return(nil, dbErr)
-		mockLogger.EXPECT().LogError("service — failed to get comment tree", dbErr, "layer", "service.impl")
-		comments, err := svc.GetComments(ctx, params)
-		require.Nil(t, comments)
+		mockStorage.EXPECT().UseChallenge(ctx, "abc", gomock.Any()).Return(dbErr)
+		mockLogger.EXPECT().LogError("service — failed to use challenge", dbErr, "layer", "service.impl")
+		_, _, err := svc.CreateComment(ctx, comment, solve(challenge, 0))
 		require.EqualError(t, err, "db down")
 	})
 
-	t.Run("success with no roots", func(t *testing.T) {
-		mockStorage.EXPECT().GetRootComments(ctx, params).Return([]models.Comment{}, nil)
-		comments, err := svc.GetComments(ctx, params)
+	t.Run("refused comments keep their proof", func(t *testing.T) {
+		filters, err := filter.New(config.Filters{RepeatedChars: config.LimitFilter{Action: "reject", Max: 3}}, mockStorage)
+		require.NoError(t, err)
+		filtered := &Service{logger: mockLogger, config: cfg, storage: mockStorage, filters: filters}
+		proof := solve(svc.newChallenge("def", 0, time.Now().Add(time.Minute)), 0)
+
+		_, _, err = filtered.CreateComment(ctx, models.Comment{Content: "", Author: "anon"}, proof)
+		require.ErrorIs(t, err, errs.ErrEmptyContent)
+
+		_, _, err = filtered.CreateComment(ctx, models.Comment{Content: "hello!!!!", Author: "anon"}, proof)
+		require.ErrorIs(t, err, errs.ErrRepeatedChars)
+
+		gomock.InOrder(
+			mockStorage.EXPECT().UseChallenge(ctx, "def", gomock.Any()).Return(nil),
+			mockStorage.EXPECT().CreateComment(ctx, approved).Return(int64(4), nil),
+		)
+		_, _, err = filtered.CreateComment(ctx, comment, proof)
 		require.NoError(t, err)
-		require.Empty(t, comments)
 	})
 
-	t.Run("success with roots and trees", func(t *testing.T) {
+}
 
-		roots := []models.Comment{{ID: 1}, {ID: 2}}
-		flat1 := []models.Comment{{ID: 1}, {ID: 3, ParentID: ptr(1)}}
+func TestLeadingZeroBits(t *testing.T) {
+	require.Equal(t, 0, leadingZeroBits([]byte{0x80, 0}))
+	require.Equal(t, 7, leadingZeroBits([]byte{0x01, 0xff}))
+	require.Equal(t, 12, leadingZeroBits([]byte{0x00, 0x0f}))
+	require.Equal(t, 16, leadingZeroBits([]byte{0x00, 0x00}))
+}
 
-		mockStorage.EXPECT().GetRootComments(ctx, params).Return(roots, nil)
-		mockStorage.EXPECT().GetCommentTree(ctx, int64(1)).Return(flat1, nil)
-		mockStorage.EXPECT().GetCommentTree(ctx, int64(2)).Return([]models.Comment{{ID: 2}}, nil)
+func TestService_DeleteComment(t *testing.T) {
 
-		comments, err := svc.GetComments(ctx, params)
+	ctx := context.Background()
+	commentID := int64(123)
+	owner := models.Identity{Subject: "user"}
+	existing := models.Comment{ID: commentID, Content: "text", Author: "user", Status: models.StatusApproved}
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := mockLogger.NewMockLogger(controller)
+	mockStorage := mockStorage.NewMockStorage(controller)
+
+	svc := &Service{logger: mockLogger, config: config.Comments{AuthorSecret: "secret"}, storage: mockStorage}
+
+	t.Run("storage.DeleteComment succeeds", func(t *testing.T) {
+		mockStorage.EXPECT().GetComment(ctx, commentID).Return(existing, nil)
+		mockStorage.EXPECT().DeleteComment(ctx, commentID).Return(nil)
+		err := svc.DeleteComment(ctx, commentID, owner)
 		require.NoError(t, err)
-		require.Len(t, comments, 2)
-		require.Equal(t, int64(1), comments[0].ID)
-		require.Len(t, comments[0].Children, 1)
-		require.Equal(t, int64(3), comments[0].Children[0].ID)
-		require.Equal(t, int64(2), comments[1].ID)
-		require.Empty(t, comments[1].Children)
+	})
 
+	t.Run("moderator deletes another author's comment", func(t *testing.T) {
+		mockStorage.EXPECT().GetComment(ctx, commentID).Return(existing, nil)
+		mockStorage.EXPECT().DeleteComment(ctx, commentID).Return(nil)
+		err := svc.DeleteComment(ctx, commentID, models.Identity{Subject: "mod", Roles: []string{"moderator"}})
+		require.NoError(t, err)
 	})
 
-}
+	t.Run("another author is forbidden", func(t *testing.T) {
+		mockStorage.EXPECT().GetComment(ctx, commentID).Return(existing, nil)
+		err := svc.DeleteComment(ctx, commentID, models.Identity{Subject: "someone"})
+		require.ErrorIs(t, err, errs.ErrForbidden)
+	})
 
-func TestBuildTree(t *testing.T) {
-	t.Run("empty comments", func(t *testing.T) {
-		result := buildTree([]models.Comment{})
-		require.Empty(t, result)
+	t.Run("anonymous caller is forbidden", func(t *testing.T) {
+		mockStorage.EXPECT().GetComment(ctx, commentID).Return(existing, nil)
+		err := svc.DeleteComment(ctx, commentID, models.Identity{})
+		require.ErrorIs(t, err, errs.ErrForbidden)
 	})
 
-	t.Run("single root", func(t *testing.T) {
-		comments := []models.Comment{{ID: 1}}
-		result := buildTree(comments)
-		require.Len(t, result, 1)
-		require.Equal(t, int64(1), result[0].ID)
-		require.Empty(t, result[0].Children)
+	t.Run("anonymous author with their token", func(t *testing.T) {
+		mockStorage.EXPECT().GetComment(ctx, commentID).Return(existing, nil)
+		mockStorage.EXPECT().DeleteComment(ctx, commentID).Return(nil)
+		err := svc.DeleteComment(ctx, commentID, models.Identity{AuthorToken: svc.authorToken(commentID)})
+		require.NoError(t, err)
 	})
 
-	t.Run("root with child", func(t *testing.T) {
-		comments := []models.Comment{{ID: 1}, {ID: 2, ParentID: ptr(1)}}
-		result := buildTree(comments)
-		require.Len(t, result, 1)
-		require.Equal(t, int64(1), result[0].ID)
-		require.Len(t, result[0].Children, 1)
-		require.Equal(t, int64(2), result[0].Children[0].ID)
+	t.Run("token for another comment is forbidden", func(t *testing.T) {
+		mockStorage.EXPECT().GetComment(ctx, commentID).Return(existing, nil)
+		err := svc.DeleteComment(ctx, commentID, models.Identity{AuthorToken: svc.authorToken(commentID + 1)})
+		require.ErrorIs(t, err, errs.ErrForbidden)
 	})
 
-	t.Run("multiple roots", func(t *testing.T) {
-		comments := []models.Comment{{ID: 1}, {ID: 2}}
-		result := buildTree(comments)
-		require.Len(t, result, 2)
+	t.Run("token without a secret is forbidden", func(t *testing.T) {
+		unsigned := &Service{logger: mockLogger, storage: mockStorage}
+		mockStorage.EXPECT().GetComment(ctx, commentID).Return(existing, nil)
+		err := unsigned.DeleteComment(ctx, commentID, models.Identity{AuthorToken: unsigned.authorToken(commentID)})
+		require.ErrorIs(t, err, errs.ErrForbidden)
 	})
 
-	t.Run("nested children", func(t *testing.T) {
-		comments := []models.Comment{{ID: 1}, {ID: 2, ParentID: ptr(1)}, {ID: 3, ParentID: ptr(2)}}
-		result := buildTree(comments)
-		require.Len(t, result, 1)
-		require.Len(t, result[0].Children, 1)
-		require.Len(t, result[0].Children[0].Children, 1)
-		require.Equal(t, int64(3), result[0].Children[0].Children[0].ID)
+	t.Run("already deleted", func(t *testing.T) {
+		deleted := existing
+		deleted.DeletedAt = new(time.Time)
+		mockStorage.EXPECT().GetComment(ctx, commentID).Return(deleted, nil)
+		err := svc.DeleteComment(ctx, commentID, owner)
+		require.ErrorIs(t, err, errs.ErrCommentNotFound)
+	})
+
+	t.Run("storage.GetComment ErrCommentNotFound", func(t *testing.T) {
+		mockStorage.EXPECT().GetComment(ctx, commentID).Return(models.Comment{}, errs.ErrCommentNotFound)
+		err := svc.DeleteComment(ctx, commentID, owner)
+		require.ErrorIs(t, err, errs.ErrCommentNotFound)
+	})
+
+	t.Run("storage.GetComment generic error", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().GetComment(ctx, commentID).Return(models.Comment{}, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to get comment", dbErr, "id", commentID, "layer", "service.impl")
+		err := svc.DeleteComment(ctx, commentID, owner)
+		require.EqualError(t, err, "db down")
+	})
+
+	t.Run("storage.DeleteComment ErrCommentNotFound", func(t *testing.T) {
+		mockStorage.EXPECT().GetComment(ctx, commentID).Return(existing, nil)
+		mockStorage.EXPECT().DeleteComment(ctx, commentID).Return(errs.ErrCommentNotFound)
+		err := svc.DeleteComment(ctx, commentID, owner)
+		require.ErrorIs(t, err, errs.ErrCommentNotFound)
+	})
+
+	t.Run("storage.DeleteComment generic error", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().GetComment(ctx, commentID).Return(existing, nil)
+		mockStorage.EXPECT().DeleteComment(ctx, commentID).Return(dbErr)
+		mockLogger.EXPECT().LogError("service — failed to delete comment", dbErr, "id", commentID, "layer", "service.impl")
+		err := svc.DeleteComment(ctx, commentID, owner)
+		require.EqualError(t, err, "db down")
+	})
+}
+
+func TestService_UpdateComment(t *testing.T) {
+
+	ctx := context.Background()
+	controller := gomock.NewController(t)
+
+	defer controller.Finish()
+
+	mockLogger := mockLogger.NewMockLogger(controller)
+	mockStorage := mockStorage.NewMockStorage(controller)
+
+	svc := &Service{logger: mockLogger, storage: mockStorage}
+	owner := models.Identity{Subject: "user"}
+	existing := models.Comment{ID: 123, Content: "typo", Author: "user", Status: models.StatusApproved}
+	comment := models.Comment{ID: 123, Content: "fixed", Author: "user"}
+
+	t.Run("validateComment error", func(t *testing.T) {
+		mockStorage.EXPECT().GetComment(ctx, comment.ID).Return(existing, nil)
+		invalid := comment
+		invalid.Content = ""
+		_, err := svc.UpdateComment(ctx, invalid, owner)
+		require.ErrorIs(t, err, errs.ErrEmptyContent)
+	})
+
+	t.Run("pending comment is out of the author's reach", func(t *testing.T) {
+		pending := existing
+		pending.Status = models.StatusPending
+		mockStorage.EXPECT().GetComment(ctx, comment.ID).Return(pending, nil)
+		_, err := svc.UpdateComment(ctx, comment, owner)
+		require.ErrorIs(t, err, errs.ErrCommentNotFound)
+	})
+
+	t.Run("moderator edits a rejected comment", func(t *testing.T) {
+		rejected := existing
+		rejected.Status = models.StatusRejected
+		mockStorage.EXPECT().GetComment(ctx, comment.ID).Return(rejected, nil)
+		mockStorage.EXPECT().UpdateComment(ctx, comment).Return(comment, nil)
+		_, err := svc.UpdateComment(ctx, comment, models.Identity{Subject: "mod", Roles: []string{"moderator"}})
+		require.NoError(t, err)
+	})
+
+	t.Run("another author is forbidden", func(t *testing.T) {
+		mockStorage.EXPECT().GetComment(ctx, comment.ID).Return(existing, nil)
+		_, err := svc.UpdateComment(ctx, comment, models.Identity{Subject: "someone"})
+		require.ErrorIs(t, err, errs.ErrForbidden)
+	})
+
+	t.Run("storage.UpdateComment succeeds", func(t *testing.T) {
+		mockStorage.EXPECT().GetComment(ctx, comment.ID).Return(existing, nil)
+		mockStorage.EXPECT().UpdateComment(ctx, comment).Return(comment, nil)
+		updated, err := svc.UpdateComment(ctx, models.Comment{ID: 123, Content: "fixed"}, owner)
+		require.NoError(t, err)
+		require.Equal(t, comment, updated)
+	})
+
+	t.Run("moderator edit keeps the author", func(t *testing.T) {
+		mockStorage.EXPECT().GetComment(ctx, comment.ID).Return(existing, nil)
+		mockStorage.EXPECT().UpdateComment(ctx, comment).Return(comment, nil)
+		_, err := svc.UpdateComment(ctx, models.Comment{ID: 123, Content: "fixed"}, models.Identity{Subject: "mod", Roles: []string{"moderator"}})
+		require.NoError(t, err)
+	})
+
+	t.Run("storage.UpdateComment ErrCommentNotFound", func(t *testing.T) {
+		mockStorage.EXPECT().GetComment(ctx, comment.ID).Return(existing, nil)
+		mockStorage.EXPECT().UpdateComment(ctx, comment).Return(models.Comment{}, errs.ErrCommentNotFound)
+		_, err := svc.UpdateComment(ctx, comment, owner)
+		require.ErrorIs(t, err, errs.ErrCommentNotFound)
+	})
+
+	t.Run("storage.UpdateComment generic error", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().GetComment(ctx, comment.ID).Return(existing, nil)
+		mockStorage.EXPECT().UpdateComment(ctx, comment).Return(models.Comment{}, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to update comment", dbErr, "id", comment.ID, "layer", "service.impl")
+		_, err := svc.UpdateComment(ctx, comment, owner)
+		require.EqualError(t, err, "db down")
+	})
+
+}
+
+func TestService_GetComment(t *testing.T) {
+
+	ctx := context.Background()
+	commentID := int64(3)
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := mockLogger.NewMockLogger(controller)
+	mockStorage := mockStorage.NewMockStorage(controller)
+
+	svc := &Service{logger: mockLogger, storage: mockStorage}
+
+	t.Run("storage.GetComment ErrCommentNotFound", func(t *testing.T) {
+		mockStorage.EXPECT().GetComment(ctx, commentID).Return(models.Comment{}, errs.ErrCommentNotFound)
+		_, err := svc.GetComment(ctx, commentID)
+		require.ErrorIs(t, err, errs.ErrCommentNotFound)
+	})
+
+	t.Run("storage.GetComment generic error", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().GetComment(ctx, commentID).Return(models.Comment{}, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to get comment", dbErr, "id", commentID, "layer", "service.impl")
+		_, err := svc.GetComment(ctx, commentID)
+		require.EqualError(t, err, "db down")
+	})
+
+	t.Run("deleted comment is a tombstone", func(t *testing.T) {
+		deletedAt := time.Now()
+		mockStorage.EXPECT().GetComment(ctx, commentID).Return(models.Comment{ID: commentID, Content: "secret", Status: models.StatusApproved, DeletedAt: &deletedAt}, nil)
+		comment, err := svc.GetComment(ctx, commentID)
+		require.NoError(t, err)
+		require.Equal(t, deletedPlaceholder, comment.Content)
+	})
+
+	t.Run("unapproved comment is hidden", func(t *testing.T) {
+		mockStorage.EXPECT().GetComment(ctx, commentID).Return(models.Comment{ID: commentID, Status: models.StatusPending}, nil)
+		_, err := svc.GetComment(ctx, commentID)
+		require.ErrorIs(t, err, errs.ErrCommentNotFound)
+	})
+
+	t.Run("storage.GetAncestors succeeds", func(t *testing.T) {
+		ancestors := []models.Comment{{ID: 1}, {ID: 2, ParentID: ptr(1)}}
+		mockStorage.EXPECT().GetAncestors(ctx, commentID).Return(ancestors, nil)
+		result, err := svc.GetAncestors(ctx, commentID)
+		require.NoError(t, err)
+		require.Equal(t, ancestors, result)
+	})
+
+	t.Run("storage.GetAncestors fails", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().GetAncestors(ctx, commentID).Return(nil, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to get ancestors", dbErr, "id", commentID, "layer", "service.impl")
+		_, err := svc.GetAncestors(ctx, commentID)
+		require.EqualError(t, err, "db down")
+	})
+
+}
+
+func TestService_GetRevisions(t *testing.T) {
+
+	ctx := context.Background()
+	commentID := int64(123)
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := mockLogger.NewMockLogger(controller)
+	mockStorage := mockStorage.NewMockStorage(controller)
+
+	svc := &Service{logger: mockLogger, storage: mockStorage}
+
+	t.Run("storage.GetRevisions succeeds", func(t *testing.T) {
+		revisions := []models.Revision{{ID: 1, CommentID: commentID, Content: "old"}}
+		mockStorage.EXPECT().GetRevisions(ctx, commentID).Return(revisions, nil)
+		result, err := svc.GetRevisions(ctx, commentID)
+		require.NoError(t, err)
+		require.Equal(t, revisions, result)
+	})
+
+	t.Run("storage.GetRevisions ErrCommentNotFound", func(t *testing.T) {
+		mockStorage.EXPECT().GetRevisions(ctx, commentID).Return(nil, errs.ErrCommentNotFound)
+		_, err := svc.GetRevisions(ctx, commentID)
+		require.ErrorIs(t, err, errs.ErrCommentNotFound)
+	})
+
+	t.Run("storage.GetRevisions generic error", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().GetRevisions(ctx, commentID).Return(nil, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to get revisions", dbErr, "id", commentID, "layer", "service.impl")
+		_, err := svc.GetRevisions(ctx, commentID)
+		require.EqualError(t, err, "db down")
+	})
+
+}
+
+func TestService_GetComments(t *testing.T) {
+
+	ctx := context.Background()
+	params := models.QueryParams{}
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := mockLogger.NewMockLogger(controller)
+	mockStorage := mockStorage.NewMockStorage(controller)
+
+	svc := &Service{logger: mockLogger, storage: mockStorage}
+
+	t.Run("thread key too long", func(t *testing.T) {
+		_, err := svc.GetComments(ctx, models.QueryParams{ThreadKey: strings.Repeat("ж", maxThreadKeyLength+1)})
+		require.ErrorIs(t, err, errs.ErrInvalidThreadKey)
+	})
+
+	t.Run("GetRootComments fails", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().GetRootComments(ctx, params).Return(nil, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to get root comments", dbErr, "layer", "service.impl")
+		comments, err := svc.GetComments(ctx, params)
+		require.Nil(t, comments)
+		require.EqualError(t, err, "db down")
+	})
+
+	t.Run("GetCommentTrees fails", func(t *testing.T) {
+		roots := []models.Comment{{ID: 1}}
+		mockStorage.EXPECT().GetRootComments(ctx, params).Return(roots, nil)
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().GetCommentTrees(ctx, []int64{1}, params.Sort, params.TreeLimits).Return(nil, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to get comment trees", dbErr, "layer", "service.impl")
+		comments, err := svc.GetComments(ctx, params)
+		require.Nil(t, comments)
+		require.EqualError(t, err, "db down")
+	})
+
+	t.Run("GetReactions fails", func(t *testing.T) {
+		roots := []models.Comment{{ID: 1}}
+		mockStorage.EXPECT().GetRootComments(ctx, params).Return(roots, nil)
+		mockStorage.EXPECT().GetCommentTrees(ctx, []int64{1}, params.Sort, params.TreeLimits).Return(roots, nil)
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().GetReactions(ctx, []int64{1}).Return(nil, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to get reactions", dbErr, "layer", "service.impl")
+		comments, err := svc.GetComments(ctx, params)
+		require.Nil(t, comments)
+		require.EqualError(t, err, "db down")
+	})
+
+	t.Run("success with no roots", func(t *testing.T) {
+		mockStorage.EXPECT().GetRootComments(ctx, params).Return([]models.Comment{}, nil)
+		comments, err := svc.GetComments(ctx, params)
+		require.NoError(t, err)
+		require.Empty(t, comments)
+	})
+
+	t.Run("success with roots and trees", func(t *testing.T) {
+
+		roots := []models.Comment{{ID: 2}, {ID: 1}}
+		flat := []models.Comment{{ID: 1}, {ID: 2}, {ID: 3, ParentID: ptr(1)}}
+
+		mockStorage.EXPECT().GetRootComments(ctx, params).Return(roots, nil)
+		mockStorage.EXPECT().GetCommentTrees(ctx, []int64{2, 1}, params.Sort, params.TreeLimits).Return(flat, nil)
+		mockStorage.EXPECT().GetReactions(ctx, []int64{1, 2, 3}).Return(map[int64]map[string]int{3: {"👍": 2}}, nil)
+
+		comments, err := svc.GetComments(ctx, params)
+		require.NoError(t, err)
+		require.Len(t, comments, 2)
+		require.Equal(t, int64(2), comments[0].ID)
+		require.Empty(t, comments[0].Children)
+		require.Nil(t, comments[0].Reactions)
+		require.Equal(t, int64(1), comments[1].ID)
+		require.Len(t, comments[1].Children, 1)
+		require.Equal(t, int64(3), comments[1].Children[0].ID)
+		require.Equal(t, map[string]int{"👍": 2}, comments[1].Children[0].Reactions)
+
+	})
+
+}
+
+func TestService_GetChildren(t *testing.T) {
+
+	ctx := context.Background()
+	parentID := int64(1)
+	params := models.QueryParams{Page: 1, Limit: 2, TreeLimits: models.TreeLimits{MaxDepth: 1, MaxChildren: 1}}
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := mockLogger.NewMockLogger(controller)
+	mockStorage := mockStorage.NewMockStorage(controller)
+
+	svc := &Service{logger: mockLogger, storage: mockStorage}
+
+	t.Run("unknown parent", func(t *testing.T) {
+		mockStorage.EXPECT().GetComment(ctx, parentID).Return(models.Comment{}, errs.ErrCommentNotFound)
+		_, _, err := svc.GetChildren(ctx, parentID, params)
+		require.ErrorIs(t, err, errs.ErrCommentNotFound)
+	})
+
+	t.Run("pending parent", func(t *testing.T) {
+		mockStorage.EXPECT().GetComment(ctx, parentID).Return(models.Comment{ID: parentID, Status: models.StatusPending}, nil)
+		_, _, err := svc.GetChildren(ctx, parentID, params)
+		require.ErrorIs(t, err, errs.ErrCommentNotFound)
+	})
+
+	t.Run("storage.GetChildren fails", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().GetComment(ctx, parentID).Return(models.Comment{ID: parentID, Status: models.StatusApproved}, nil)
+		mockStorage.EXPECT().GetChildren(ctx, parentID, params).Return(nil, 0, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to get children", dbErr, "id", parentID, "layer", "service.impl")
+		_, _, err := svc.GetChildren(ctx, parentID, params)
+		require.EqualError(t, err, "db down")
+	})
+
+	t.Run("success with truncated subtrees", func(t *testing.T) {
+		children := []models.Comment{{ID: 2, ParentID: ptr(1)}, {ID: 3, ParentID: ptr(1)}}
+		flat := []models.Comment{
+			{ID: 2, ParentID: ptr(1), ChildrenCount: 3},
+			{ID: 3, ParentID: ptr(1)},
+			{ID: 4, ParentID: ptr(2)},
+		}
+		mockStorage.EXPECT().GetComment(ctx, parentID).Return(models.Comment{ID: parentID, Status: models.StatusApproved}, nil)
+		mockStorage.EXPECT().GetChildren(ctx, parentID, params).Return(children, 5, nil)
+		mockStorage.EXPECT().GetCommentTrees(ctx, []int64{2, 3}, params.Sort, params.TreeLimits).Return(flat, nil)
+		mockStorage.EXPECT().GetReactions(ctx, []int64{2, 3, 4}).Return(map[int64]map[string]int{}, nil)
+		result, total, err := svc.GetChildren(ctx, parentID, params)
+		require.NoError(t, err)
+		require.Equal(t, 5, total)
+		require.Len(t, result, 2)
+		require.Len(t, result[0].Children, 1)
+		require.True(t, result[0].HasMoreChildren)
+		require.False(t, result[1].HasMoreChildren)
+	})
+
+}
+
+func TestService_CountComments(t *testing.T) {
+
+	ctx := context.Background()
+	params := models.QueryParams{}
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := mockLogger.NewMockLogger(controller)
+	mockStorage := mockStorage.NewMockStorage(controller)
+
+	svc := &Service{logger: mockLogger, storage: mockStorage}
+
+	t.Run("storage.CountRootComments succeeds", func(t *testing.T) {
+		mockStorage.EXPECT().CountRootComments(ctx, params).Return(42, nil)
+		total, err := svc.CountComments(ctx, params)
+		require.NoError(t, err)
+		require.Equal(t, 42, total)
+	})
+
+	t.Run("storage.CountRootComments fails", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().CountRootComments(ctx, params).Return(0, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to count root comments", dbErr, "layer", "service.impl")
+		_, err := svc.CountComments(ctx, params)
+		require.EqualError(t, err, "db down")
+	})
+
+}
+
+func TestService_VoteComment(t *testing.T) {
+
+	ctx := context.Background()
+	commentID := int64(5)
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := mockLogger.NewMockLogger(controller)
+	mockStorage := mockStorage.NewMockStorage(controller)
+
+	svc := &Service{logger: mockLogger, storage: mockStorage}
+
+	t.Run("empty voter", func(t *testing.T) {
+		_, err := svc.VoteComment(ctx, commentID, " ", 1)
+		require.ErrorIs(t, err, errs.ErrEmptyVoter)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		_, err := svc.VoteComment(ctx, commentID, "alice", 2)
+		require.ErrorIs(t, err, errs.ErrInvalidVote)
+	})
+
+	t.Run("storage.VoteComment succeeds", func(t *testing.T) {
+		voted := models.Comment{ID: commentID, Score: 1, Upvotes: 1}
+		mockStorage.EXPECT().VoteComment(ctx, commentID, "alice", 1).Return(voted, nil)
+		comment, err := svc.VoteComment(ctx, commentID, "alice", 1)
+		require.NoError(t, err)
+		require.Equal(t, voted, comment)
+	})
+
+	t.Run("storage.VoteComment ErrCommentNotFound", func(t *testing.T) {
+		mockStorage.EXPECT().VoteComment(ctx, commentID, "alice", -1).Return(models.Comment{}, errs.ErrCommentNotFound)
+		_, err := svc.VoteComment(ctx, commentID, "alice", -1)
+		require.ErrorIs(t, err, errs.ErrCommentNotFound)
+	})
+
+	t.Run("storage.VoteComment generic error", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().VoteComment(ctx, commentID, "alice", 0).Return(models.Comment{}, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to vote on comment", dbErr, "id", commentID, "layer", "service.impl")
+		_, err := svc.VoteComment(ctx, commentID, "alice", 0)
+		require.EqualError(t, err, "db down")
+	})
+
+}
+
+func TestService_AddReaction(t *testing.T) {
+
+	ctx := context.Background()
+	commentID := int64(5)
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := mockLogger.NewMockLogger(controller)
+	mockStorage := mockStorage.NewMockStorage(controller)
+
+	svc := &Service{logger: mockLogger, config: config.Comments{Reactions: []string{"👍", "🎉"}}, storage: mockStorage}
+
+	t.Run("empty reactor", func(t *testing.T) {
+		_, err := svc.AddReaction(ctx, commentID, "👍", "")
+		require.ErrorIs(t, err, errs.ErrEmptyReactor)
+	})
+
+	t.Run("reaction not allowed", func(t *testing.T) {
+		_, err := svc.AddReaction(ctx, commentID, "💩", "alice")
+		require.ErrorIs(t, err, errs.ErrInvalidReaction)
+	})
+
+	t.Run("storage.AddReaction succeeds", func(t *testing.T) {
+		mockStorage.EXPECT().AddReaction(ctx, commentID, "🎉", "alice").Return(map[string]int{"🎉": 1}, nil)
+		counts, err := svc.AddReaction(ctx, commentID, "🎉", "alice")
+		require.NoError(t, err)
+		require.Equal(t, map[string]int{"🎉": 1}, counts)
+	})
+
+	t.Run("storage.AddReaction ErrCommentNotFound", func(t *testing.T) {
+		mockStorage.EXPECT().AddReaction(ctx, commentID, "👍", "alice").Return(nil, errs.ErrCommentNotFound)
+		_, err := svc.AddReaction(ctx, commentID, "👍", "alice")
+		require.ErrorIs(t, err, errs.ErrCommentNotFound)
+	})
+
+	t.Run("storage.AddReaction generic error", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().AddReaction(ctx, commentID, "👍", "alice").Return(nil, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to add reaction", dbErr, "id", commentID, "layer", "service.impl")
+		_, err := svc.AddReaction(ctx, commentID, "👍", "alice")
+		require.EqualError(t, err, "db down")
+	})
+
+}
+
+func TestService_RemoveReaction(t *testing.T) {
+
+	ctx := context.Background()
+	commentID := int64(5)
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := mockLogger.NewMockLogger(controller)
+	mockStorage := mockStorage.NewMockStorage(controller)
+
+	svc := &Service{logger: mockLogger, config: config.Comments{Reactions: []string{"👍"}}, storage: mockStorage}
+
+	t.Run("reaction not allowed", func(t *testing.T) {
+		_, err := svc.RemoveReaction(ctx, commentID, "🎉", "alice")
+		require.ErrorIs(t, err, errs.ErrInvalidReaction)
+	})
+
+	t.Run("storage.RemoveReaction succeeds", func(t *testing.T) {
+		mockStorage.EXPECT().RemoveReaction(ctx, commentID, "👍", "alice").Return(map[string]int{}, nil)
+		counts, err := svc.RemoveReaction(ctx, commentID, "👍", "alice")
+		require.NoError(t, err)
+		require.Empty(t, counts)
+	})
+
+	t.Run("storage.RemoveReaction generic error", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().RemoveReaction(ctx, commentID, "👍", "alice").Return(nil, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to remove reaction", dbErr, "id", commentID, "layer", "service.impl")
+		_, err := svc.RemoveReaction(ctx, commentID, "👍", "alice")
+		require.EqualError(t, err, "db down")
+	})
+
+}
+
+func TestService_ResolveRoles(t *testing.T) {
+
+	ctx := context.Background()
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := mockLogger.NewMockLogger(controller)
+	mockStorage := mockStorage.NewMockStorage(controller)
+
+	svc := &Service{logger: mockLogger, storage: mockStorage}
+
+	t.Run("roles from token", func(t *testing.T) {
+		identity := models.Identity{Subject: "alice", Roles: []string{"admin"}}
+		resolved, err := svc.ResolveRoles(ctx, identity)
+		require.NoError(t, err)
+		require.Equal(t, identity, resolved)
+	})
+
+	t.Run("role from users table", func(t *testing.T) {
+		mockStorage.EXPECT().GetUserRole(ctx, "bob").Return("moderator", nil)
+		resolved, err := svc.ResolveRoles(ctx, models.Identity{Subject: "bob"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"moderator"}, resolved.Roles)
+	})
+
+	t.Run("unknown user is a commenter", func(t *testing.T) {
+		mockStorage.EXPECT().GetUserRole(ctx, "carol").Return("", errs.ErrUserNotFound)
+		resolved, err := svc.ResolveRoles(ctx, models.Identity{Subject: "carol"})
+		require.NoError(t, err)
+		require.Equal(t, []string{"commenter"}, resolved.Roles)
+	})
+
+	t.Run("storage.GetUserRole generic error", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().GetUserRole(ctx, "dave").Return("", dbErr)
+		mockLogger.EXPECT().LogError("service — failed to get user role", dbErr, "subject", "dave", "layer", "service.impl")
+		_, err := svc.ResolveRoles(ctx, models.Identity{Subject: "dave"})
+		require.EqualError(t, err, "db down")
+	})
+
+}
+
+func TestService_CreateAPIKey(t *testing.T) {
+
+	ctx := context.Background()
+	admin := models.Identity{Subject: "root", Roles: []string{"admin"}}
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := mockLogger.NewMockLogger(controller)
+	mockStorage := mockStorage.NewMockStorage(controller)
+
+	svc := &Service{logger: mockLogger, storage: mockStorage}
+
+	t.Run("empty name", func(t *testing.T) {
+		_, _, err := svc.CreateAPIKey(ctx, " ", nil, admin)
+		require.ErrorIs(t, err, errs.ErrInvalidKeyName)
+	})
+
+	t.Run("unknown scope", func(t *testing.T) {
+		_, _, err := svc.CreateAPIKey(ctx, "shop", []string{"create", "root"}, admin)
+		require.ErrorIs(t, err, errs.ErrInvalidScope)
+	})
+
+	t.Run("stores only the hash", func(t *testing.T) {
+		var hash string
+		mockStorage.EXPECT().CreateAPIKey(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, key models.APIKey, h string) (models.APIKey, error) {
+			require.Equal(t, "shop", key.Name)
+			require.Equal(t, "root", key.CreatedBy)
+			require.Equal(t, []string{"create"}, key.Scopes)
+			hash = h
+			key.ID = 1
+			return key, nil
+		})
+		key, secret, err := svc.CreateAPIKey(ctx, "shop", []string{"create"}, admin)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(secret, key.Prefix))
+		require.True(t, strings.HasPrefix(secret, "hk_"))
+		require.NotContains(t, hash, secret)
+		require.Equal(t, hashAPIKey(secret), hash)
+	})
+
+	t.Run("name already in use", func(t *testing.T) {
+		mockStorage.EXPECT().CreateAPIKey(ctx, gomock.Any(), gomock.Any()).Return(models.APIKey{}, errs.ErrKeyNameTaken)
+		_, _, err := svc.CreateAPIKey(ctx, "shop", nil, admin)
+		require.ErrorIs(t, err, errs.ErrKeyNameTaken)
+	})
+
+	t.Run("storage.CreateAPIKey generic error", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().CreateAPIKey(ctx, gomock.Any(), gomock.Any()).Return(models.APIKey{}, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to create api key", dbErr, "layer", "service.impl")
+		_, _, err := svc.CreateAPIKey(ctx, "shop", nil, admin)
+		require.EqualError(t, err, "db down")
+	})
+
+}
+
+func TestService_AuthenticateAPIKey(t *testing.T) {
+
+	ctx := context.Background()
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := mockLogger.NewMockLogger(controller)
+	mockStorage := mockStorage.NewMockStorage(controller)
+
+	svc := &Service{logger: mockLogger, storage: mockStorage}
+
+	t.Run("unknown or revoked key", func(t *testing.T) {
+		mockStorage.EXPECT().UseAPIKey(ctx, hashAPIKey("hk_nope")).Return(models.APIKey{}, errs.ErrAPIKeyNotFound)
+		_, err := svc.AuthenticateAPIKey(ctx, "hk_nope")
+		require.ErrorIs(t, err, errs.ErrUnauthorized)
+	})
+
+	t.Run("success", func(t *testing.T) {
+		mockStorage.EXPECT().UseAPIKey(ctx, hashAPIKey("hk_good")).Return(models.APIKey{Name: "shop", Scopes: []string{"create"}}, nil)
+		identity, err := svc.AuthenticateAPIKey(ctx, "hk_good")
+		require.NoError(t, err)
+		require.Equal(t, models.Identity{Subject: "apikey:shop", Scopes: []string{"create"}}, identity)
+	})
+
+	t.Run("storage.UseAPIKey generic error", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().UseAPIKey(ctx, hashAPIKey("hk_good")).Return(models.APIKey{}, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to authenticate api key", dbErr, "layer", "service.impl")
+		_, err := svc.AuthenticateAPIKey(ctx, "hk_good")
+		require.EqualError(t, err, "db down")
+	})
+
+}
+
+func TestService_RevokeAPIKey(t *testing.T) {
+
+	ctx := context.Background()
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := mockLogger.NewMockLogger(controller)
+	mockStorage := mockStorage.NewMockStorage(controller)
+
+	svc := &Service{logger: mockLogger, storage: mockStorage}
+
+	t.Run("storage.RevokeAPIKey ErrAPIKeyNotFound", func(t *testing.T) {
+		mockStorage.EXPECT().RevokeAPIKey(ctx, int64(1)).Return(errs.ErrAPIKeyNotFound)
+		require.ErrorIs(t, svc.RevokeAPIKey(ctx, 1), errs.ErrAPIKeyNotFound)
+	})
+
+	t.Run("storage.RevokeAPIKey generic error", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().RevokeAPIKey(ctx, int64(1)).Return(dbErr)
+		mockLogger.EXPECT().LogError("service — failed to revoke api key", dbErr, "id", int64(1), "layer", "service.impl")
+		require.EqualError(t, svc.RevokeAPIKey(ctx, 1), "db down")
+	})
+
+}
+
+func TestService_Moderation(t *testing.T) {
+
+	ctx := context.Background()
+	params := models.QueryParams{Page: 1, Limit: 20}
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := mockLogger.NewMockLogger(controller)
+	mockStorage := mockStorage.NewMockStorage(controller)
+
+	svc := &Service{logger: mockLogger, storage: mockStorage}
+
+	t.Run("queue", func(t *testing.T) {
+		pending := []models.Comment{{ID: 1, Status: models.StatusPending}}
+		mockStorage.EXPECT().GetPendingComments(ctx, params).Return(pending, 1, nil)
+		comments, total, err := svc.GetPendingComments(ctx, params)
+		require.NoError(t, err)
+		require.Equal(t, 1, total)
+		require.Equal(t, pending, comments)
+	})
+
+	t.Run("queue fails", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().GetPendingComments(ctx, params).Return(nil, 0, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to get pending comments", dbErr, "layer", "service.impl")
+		_, _, err := svc.GetPendingComments(ctx, params)
+		require.EqualError(t, err, "db down")
+	})
+
+	t.Run("approve", func(t *testing.T) {
+		mockStorage.EXPECT().ModerateComment(ctx, int64(1), models.StatusApproved).Return(models.Comment{ID: 1, Status: models.StatusApproved}, nil)
+		comment, err := svc.ApproveComment(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, models.StatusApproved, comment.Status)
+	})
+
+	t.Run("reject comment not in queue", func(t *testing.T) {
+		mockStorage.EXPECT().ModerateComment(ctx, int64(2), models.StatusRejected).Return(models.Comment{}, errs.ErrCommentNotFound)
+		_, err := svc.RejectComment(ctx, 2)
+		require.ErrorIs(t, err, errs.ErrCommentNotFound)
+	})
+
+	t.Run("reject fails", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().ModerateComment(ctx, int64(3), models.StatusRejected).Return(models.Comment{}, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to moderate comment", dbErr, "id", int64(3), "status", models.StatusRejected, "layer", "service.impl")
+		_, err := svc.RejectComment(ctx, 3)
+		require.EqualError(t, err, "db down")
+	})
+
+}
+
+func TestService_ReportComment(t *testing.T) {
+
+	ctx := context.Background()
+	commentID := int64(5)
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := mockLogger.NewMockLogger(controller)
+	mockStorage := mockStorage.NewMockStorage(controller)
+
+	svc := &Service{logger: mockLogger, config: config.Comments{ReportThreshold: 3}, storage: mockStorage}
+
+	t.Run("empty reporter", func(t *testing.T) {
+		_, err := svc.ReportComment(ctx, models.Report{CommentID: commentID, Reporter: " ", Reason: models.ReasonSpam})
+		require.ErrorIs(t, err, errs.ErrEmptyReporter)
+	})
+
+	t.Run("invalid reason", func(t *testing.T) {
+		_, err := svc.ReportComment(ctx, models.Report{CommentID: commentID, Reporter: "alice", Reason: "boring"})
+		require.ErrorIs(t, err, errs.ErrInvalidReason)
+	})
+
+	t.Run("note too long", func(t *testing.T) {
+		note := strings.Repeat("я", maxReportNoteLength+1)
+		_, err := svc.ReportComment(ctx, models.Report{CommentID: commentID, Reporter: "alice", Reason: models.ReasonOther, Note: note})
+		require.ErrorIs(t, err, errs.ErrNoteTooLong)
+	})
+
+	t.Run("storage.ReportComment succeeds", func(t *testing.T) {
+		report := models.Report{CommentID: commentID, Reporter: "alice", Reason: models.ReasonSpam, Note: "ads"}
+		hidden := models.Comment{ID: commentID, Status: models.StatusHidden}
+		mockStorage.EXPECT().ReportComment(ctx, report, 3).Return(hidden, nil)
+		comment, err := svc.ReportComment(ctx, models.Report{CommentID: commentID, Reporter: "alice", Reason: models.ReasonSpam, Note: "  ads "})
+		require.NoError(t, err)
+		require.Equal(t, hidden, comment)
+	})
+
+	t.Run("storage.ReportComment ErrCommentNotFound", func(t *testing.T) {
+		report := models.Report{CommentID: commentID, Reporter: "alice", Reason: models.ReasonOffTopic}
+		mockStorage.EXPECT().ReportComment(ctx, report, 3).Return(models.Comment{}, errs.ErrCommentNotFound)
+		_, err := svc.ReportComment(ctx, report)
+		require.ErrorIs(t, err, errs.ErrCommentNotFound)
+	})
+
+	t.Run("storage.ReportComment generic error", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		report := models.Report{CommentID: commentID, Reporter: "alice", Reason: models.ReasonHarassment}
+		mockStorage.EXPECT().ReportComment(ctx, report, 3).Return(models.Comment{}, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to report comment", dbErr, "id", commentID, "layer", "service.impl")
+		_, err := svc.ReportComment(ctx, report)
+		require.EqualError(t, err, "db down")
+	})
+
+}
+
+func TestService_SearchComments(t *testing.T) {
+
+	ctx := context.Background()
+	params := models.SearchParams{Query: "hermes", Page: 1, Limit: 20}
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	mockLogger := mockLogger.NewMockLogger(controller)
+	mockStorage := mockStorage.NewMockStorage(controller)
+
+	svc := &Service{logger: mockLogger, storage: mockStorage}
+
+	t.Run("empty query", func(t *testing.T) {
+		_, _, err := svc.SearchComments(ctx, models.SearchParams{Query: "  "})
+		require.ErrorIs(t, err, errs.ErrEmptyQuery)
+	})
+
+	t.Run("storage.SearchComments succeeds", func(t *testing.T) {
+		results := []models.SearchResult{{Comment: models.Comment{ID: 1}}}
+		mockStorage.EXPECT().SearchComments(ctx, params).Return(results, 1, nil)
+		found, total, err := svc.SearchComments(ctx, params)
+		require.NoError(t, err)
+		require.Equal(t, results, found)
+		require.Equal(t, 1, total)
+	})
+
+	t.Run("storage.SearchComments fails", func(t *testing.T) {
+		dbErr := errors.New("db down")
+		mockStorage.EXPECT().SearchComments(ctx, params).Return(nil, 0, dbErr)
+		mockLogger.EXPECT().LogError("service — failed to search comments", dbErr, "layer", "service.impl")
+		_, _, err := svc.SearchComments(ctx, params)
+		require.EqualError(t, err, "db down")
+	})
+
+}
+
+func TestBuildTree(t *testing.T) {
+	t.Run("empty comments", func(t *testing.T) {
+		result := buildTree([]models.Comment{})
+		require.Empty(t, result)
+	})
+
+	t.Run("single root", func(t *testing.T) {
+		comments := []models.Comment{{ID: 1}}
+		result := buildTree(comments)
+		require.Len(t, result, 1)
+		require.Equal(t, int64(1), result[0].ID)
+		require.Empty(t, result[0].Children)
+	})
+
+	t.Run("root with child", func(t *testing.T) {
+		comments := []models.Comment{{ID: 1}, {ID: 2, ParentID: ptr(1)}}
+		result := buildTree(comments)
+		require.Len(t, result, 1)
+		require.Equal(t, int64(1), result[0].ID)
+		require.Len(t, result[0].Children, 1)
+		require.Equal(t, int64(2), result[0].Children[0].ID)
+	})
+
+	t.Run("multiple roots", func(t *testing.T) {
+		comments := []models.Comment{{ID: 1}, {ID: 2}}
+		result := buildTree(comments)
+		require.Len(t, result, 2)
+	})
+
+	t.Run("nested children", func(t *testing.T) {
+		comments := []models.Comment{{ID: 1}, {ID: 2, ParentID: ptr(1)}, {ID: 3, ParentID: ptr(2)}}
+		result := buildTree(comments)
+		require.Len(t, result, 1)
+		require.Len(t, result[0].Children, 1)
+		require.Len(t, result[0].Children[0].Children, 1)
+		require.Equal(t, int64(3), result[0].Children[0].Children[0].ID)
+	})
+
+	t.Run("soft-deleted node keeps its children", func(t *testing.T) {
+		deletedAt := time.Now()
+		comments := []models.Comment{
+			{ID: 1, Content: "root", Author: "a"},
+			{ID: 2, ParentID: ptr(1), Content: "secret", Author: "b", DeletedAt: &deletedAt},
+			{ID: 3, ParentID: ptr(2), Content: "reply", Author: "c"},
+		}
+		result := buildTree(comments)
+		require.Len(t, result, 1)
+		require.Len(t, result[0].Children, 1)
+		tomb := result[0].Children[0]
+		require.Equal(t, deletedPlaceholder, tomb.Content)
+		require.Equal(t, deletedPlaceholder, tomb.Author)
+		require.Len(t, tomb.Children, 1)
+		require.Equal(t, "reply", tomb.Children[0].Content)
+	})
+
+	t.Run("truncated children are marked", func(t *testing.T) {
+		comments := []models.Comment{{ID: 1, ChildrenCount: 2}, {ID: 2, ParentID: ptr(1), ChildrenCount: 0}}
+		result := buildTree(comments)
+		require.True(t, result[0].HasMoreChildren)
+		require.False(t, result[0].Children[0].HasMoreChildren)
 	})
 
 	t.Run("orphan child treated as root", func(t *testing.T) {
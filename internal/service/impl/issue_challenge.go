@@ -0,0 +1,57 @@
+package impl
+
+import (
+	"Hermes/internal/models"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// IssueChallenge returns a new proof-of-work challenge. Its difficulty
+// grows with the number of comments posted recently.
+func (s *Service) IssueChallenge(ctx context.Context) (models.Challenge, error) {
+
+	if !s.config.Challenge.Enabled {
+		return models.Challenge{}, nil
+	}
+
+	difficulty, err := s.challengeDifficulty(ctx)
+	if err != nil {
+		s.logger.LogError("service — failed to count recent comments", err, "layer", "service.impl")
+		return models.Challenge{}, err
+	}
+
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		s.logger.LogError("service — failed to generate challenge", err, "layer", "service.impl")
+		return models.Challenge{}, err
+	}
+
+	expiresAt := time.Now().Add(s.config.Challenge.TTL).Truncate(time.Second)
+
+	return models.Challenge{
+		Required:   true,
+		Challenge:  s.newChallenge(hex.EncodeToString(raw), difficulty, expiresAt),
+		Difficulty: difficulty,
+		ExpiresAt:  expiresAt,
+	}, nil
+
+}
+
+func (s *Service) challengeDifficulty(ctx context.Context) (int, error) {
+
+	config := s.config.Challenge
+
+	if config.RateStep <= 0 {
+		return config.MinDifficulty, nil
+	}
+
+	recent, err := s.storage.CountRecentComments(ctx, config.RateWindow)
+	if err != nil {
+		return 0, err
+	}
+
+	return min(config.MinDifficulty+recent/config.RateStep, max(config.MaxDifficulty, config.MinDifficulty)), nil
+
+}
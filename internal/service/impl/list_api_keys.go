@@ -0,0 +1,18 @@
+package impl
+
+import (
+	"Hermes/internal/models"
+	"context"
+)
+
+func (s *Service) ListAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+
+	keys, err := s.storage.ListAPIKeys(ctx)
+	if err != nil {
+		s.logger.LogError("service — failed to list api keys", err, "layer", "service.impl")
+		return nil, err
+	}
+
+	return keys, nil
+
+}
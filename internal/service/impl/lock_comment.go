@@ -0,0 +1,33 @@
+package impl
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"errors"
+)
+
+func (s *Service) LockComment(ctx context.Context, id int64) (models.Comment, error) {
+	return s.changeComment(ctx, id, s.storage.LockComment, "service — failed to lock comment")
+}
+
+func (s *Service) UnlockComment(ctx context.Context, id int64) (models.Comment, error) {
+	return s.changeComment(ctx, id, s.storage.UnlockComment, "service — failed to unlock comment")
+}
+
+// changeComment applies a moderator's change to a comment, logging
+// failures other than a missing comment as msg.
+func (s *Service) changeComment(ctx context.Context, id int64, apply func(ctx context.Context, id int64) (models.Comment, error), msg string) (models.Comment, error) {
+
+	comment, err := apply(ctx, id)
+	if err != nil {
+		if errors.Is(err, errs.ErrCommentNotFound) {
+			return models.Comment{}, err
+		}
+		s.logger.LogError(msg, err, "id", id, "layer", "service.impl")
+		return models.Comment{}, err
+	}
+
+	return comment, nil
+
+}
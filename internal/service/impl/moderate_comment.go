@@ -0,0 +1,31 @@
+package impl
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"errors"
+)
+
+func (s *Service) ApproveComment(ctx context.Context, id int64) (models.Comment, error) {
+	return s.moderate(ctx, id, models.StatusApproved)
+}
+
+func (s *Service) RejectComment(ctx context.Context, id int64) (models.Comment, error) {
+	return s.moderate(ctx, id, models.StatusRejected)
+}
+
+func (s *Service) moderate(ctx context.Context, id int64, status string) (models.Comment, error) {
+
+	comment, err := s.storage.ModerateComment(ctx, id, status)
+	if err != nil {
+		if errors.Is(err, errs.ErrCommentNotFound) {
+			return models.Comment{}, err
+		}
+		s.logger.LogError("service — failed to moderate comment", err, "id", id, "status", status, "layer", "service.impl")
+		return models.Comment{}, err
+	}
+
+	return comment, nil
+
+}
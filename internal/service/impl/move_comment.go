@@ -0,0 +1,36 @@
+package impl
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"errors"
+)
+
+// MoveComment reparents a comment and its replies under parentID, or makes
+// it a thread root when parentID is nil.
+func (s *Service) MoveComment(ctx context.Context, id int64, parentID *int64) (models.Comment, error) {
+
+	if parentID != nil {
+		if *parentID <= 0 {
+			return models.Comment{}, errs.ErrInvalidParentID
+		}
+		if *parentID == id {
+			return models.Comment{}, errs.ErrInvalidMove
+		}
+	}
+
+	comment, err := s.storage.MoveComment(ctx, id, parentID)
+	if err != nil {
+		if errors.Is(err, errs.ErrCommentNotFound) ||
+			errors.Is(err, errs.ErrParentNotFound) ||
+			errors.Is(err, errs.ErrInvalidMove) {
+			return models.Comment{}, err
+		}
+		s.logger.LogError("service — failed to move comment", err, "id", id, "layer", "service.impl")
+		return models.Comment{}, err
+	}
+
+	return comment, nil
+
+}
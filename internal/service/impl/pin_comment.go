@@ -0,0 +1,37 @@
+package impl
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"errors"
+)
+
+func (s *Service) PinComment(ctx context.Context, id int64) (models.Comment, error) {
+	return s.setPinned(ctx, id, s.storage.PinComment, "service — failed to pin comment")
+}
+
+func (s *Service) UnpinComment(ctx context.Context, id int64) (models.Comment, error) {
+	return s.setPinned(ctx, id, s.storage.UnpinComment, "service — failed to unpin comment")
+}
+
+// setPinned applies a pin change to a root comment. Replies cannot be
+// pinned because only root comments are listed.
+func (s *Service) setPinned(ctx context.Context, id int64, apply func(ctx context.Context, id int64) (models.Comment, error), msg string) (models.Comment, error) {
+
+	comment, err := s.storage.GetComment(ctx, id)
+	if err != nil {
+		if errors.Is(err, errs.ErrCommentNotFound) {
+			return models.Comment{}, err
+		}
+		s.logger.LogError("service — failed to get comment", err, "id", id, "layer", "service.impl")
+		return models.Comment{}, err
+	}
+
+	if comment.ParentID != nil {
+		return models.Comment{}, errs.ErrNotRootComment
+	}
+
+	return s.changeComment(ctx, id, apply, msg)
+
+}
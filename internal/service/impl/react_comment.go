@@ -0,0 +1,63 @@
+package impl
+
+import (
+	"Hermes/internal/errs"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+)
+
+func (s *Service) AddReaction(ctx context.Context, commentID int64, emoji, reactor string) (map[string]int, error) {
+
+	if err := s.validateReaction(emoji, reactor); err != nil {
+		return nil, err
+	}
+
+	counts, err := s.storage.AddReaction(ctx, commentID, emoji, reactor)
+	if err != nil {
+		if errors.Is(err, errs.ErrCommentNotFound) {
+			return nil, err
+		}
+		s.logger.LogError("service — failed to add reaction", err, "id", commentID, "layer", "service.impl")
+		return nil, err
+	}
+
+	return counts, nil
+
+}
+
+func (s *Service) RemoveReaction(ctx context.Context, commentID int64, emoji, reactor string) (map[string]int, error) {
+
+	if err := s.validateReaction(emoji, reactor); err != nil {
+		return nil, err
+	}
+
+	counts, err := s.storage.RemoveReaction(ctx, commentID, emoji, reactor)
+	if err != nil {
+		if errors.Is(err, errs.ErrCommentNotFound) {
+			return nil, err
+		}
+		s.logger.LogError("service — failed to remove reaction", err, "id", commentID, "layer", "service.impl")
+		return nil, err
+	}
+
+	return counts, nil
+
+}
+
+// validateReaction checks the reactor and that emoji is on the configured
+// allow-list.
+func (s *Service) validateReaction(emoji, reactor string) error {
+
+	if strings.TrimSpace(reactor) == "" {
+		return errs.ErrEmptyReactor
+	}
+
+	if !slices.Contains(s.config.Reactions, emoji) {
+		return errs.ErrInvalidReaction
+	}
+
+	return nil
+
+}
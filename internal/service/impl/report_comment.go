@@ -0,0 +1,55 @@
+package impl
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+const maxReportNoteLength = 1000
+
+var reportReasons = []string{
+	models.ReasonSpam,
+	models.ReasonHarassment,
+	models.ReasonHateSpeech,
+	models.ReasonOffTopic,
+	models.ReasonOther,
+}
+
+func (s *Service) ReportComment(ctx context.Context, report models.Report) (models.Comment, error) {
+
+	report.Note = strings.TrimSpace(report.Note)
+
+	if err := validateReport(report); err != nil {
+		return models.Comment{}, err
+	}
+
+	comment, err := s.storage.ReportComment(ctx, report, s.config.ReportThreshold)
+	if err != nil {
+		if errors.Is(err, errs.ErrCommentNotFound) {
+			return models.Comment{}, err
+		}
+		s.logger.LogError("service — failed to report comment", err, "id", report.CommentID, "layer", "service.impl")
+		return models.Comment{}, err
+	}
+
+	return comment, nil
+
+}
+
+func validateReport(report models.Report) error {
+	if strings.TrimSpace(report.Reporter) == "" {
+		return errs.ErrEmptyReporter
+	}
+	if !slices.Contains(reportReasons, report.Reason) {
+		return errs.ErrInvalidReason
+	}
+	if utf8.RuneCountInString(report.Note) > maxReportNoteLength {
+		return errs.ErrNoteTooLong
+	}
+	return nil
+}
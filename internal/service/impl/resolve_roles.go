@@ -0,0 +1,33 @@
+package impl
+
+import (
+	"Hermes/internal/auth"
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"errors"
+)
+
+// ResolveRoles fills in the roles of an authenticated caller whose token
+// carried none: the role stored in the users table, or commenter for
+// subjects without a row there.
+func (s *Service) ResolveRoles(ctx context.Context, identity models.Identity) (models.Identity, error) {
+
+	if len(identity.Roles) > 0 {
+		return identity, nil
+	}
+
+	role, err := s.storage.GetUserRole(ctx, identity.Subject)
+	if err != nil {
+		if !errors.Is(err, errs.ErrUserNotFound) {
+			s.logger.LogError("service — failed to get user role", err, "subject", identity.Subject, "layer", "service.impl")
+			return models.Identity{}, err
+		}
+		role = auth.RoleCommenter
+	}
+
+	identity.Roles = []string{role}
+
+	return identity, nil
+
+}
@@ -0,0 +1,21 @@
+package impl
+
+import (
+	"Hermes/internal/errs"
+	"context"
+	"errors"
+)
+
+func (s *Service) RevokeAPIKey(ctx context.Context, id int64) error {
+
+	if err := s.storage.RevokeAPIKey(ctx, id); err != nil {
+		if errors.Is(err, errs.ErrAPIKeyNotFound) {
+			return err
+		}
+		s.logger.LogError("service — failed to revoke api key", err, "id", id, "layer", "service.impl")
+		return err
+	}
+
+	return nil
+
+}
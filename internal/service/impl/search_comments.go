@@ -0,0 +1,28 @@
+package impl
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"strings"
+)
+
+func (s *Service) SearchComments(ctx context.Context, params models.SearchParams) ([]models.SearchResult, int, error) {
+
+	if strings.TrimSpace(params.Query) == "" {
+		return nil, 0, errs.ErrEmptyQuery
+	}
+
+	if err := validateThreadKey(params.ThreadKey); err != nil {
+		return nil, 0, err
+	}
+
+	results, total, err := s.storage.SearchComments(ctx, params)
+	if err != nil {
+		s.logger.LogError("service — failed to search comments", err, "layer", "service.impl")
+		return nil, 0, err
+	}
+
+	return results, total, nil
+
+}
@@ -0,0 +1,36 @@
+package impl
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"errors"
+)
+
+// UpdateComment edits the content of a comment on behalf of caller. The
+// comment keeps its original author, even when a moderator edits it.
+func (s *Service) UpdateComment(ctx context.Context, comment models.Comment, caller models.Identity) (models.Comment, error) {
+
+	existing, err := s.authorize(ctx, comment.ID, caller)
+	if err != nil {
+		return models.Comment{}, err
+	}
+
+	comment.Author = existing.Author
+
+	if err := validateComment(comment); err != nil {
+		return models.Comment{}, err
+	}
+
+	updated, err := s.storage.UpdateComment(ctx, comment)
+	if err != nil {
+		if errors.Is(err, errs.ErrCommentNotFound) {
+			return models.Comment{}, err
+		}
+		s.logger.LogError("service — failed to update comment", err, "id", comment.ID, "layer", "service.impl")
+		return models.Comment{}, err
+	}
+
+	return updated, nil
+
+}
@@ -4,8 +4,12 @@ import (
 	"Hermes/internal/errs"
 	"Hermes/internal/models"
 	"strings"
+	"unicode/utf8"
 )
 
+// maxThreadKeyLength is counted in characters, like the VARCHAR(255) column.
+const maxThreadKeyLength = 255
+
 func validateComment(comment models.Comment) error {
 	if strings.TrimSpace(comment.Content) == "" {
 		return errs.ErrEmptyContent
@@ -13,5 +17,12 @@ func validateComment(comment models.Comment) error {
 	if strings.TrimSpace(comment.Author) == "" {
 		return errs.ErrEmptyAuthor
 	}
+	return validateThreadKey(comment.ThreadKey)
+}
+
+func validateThreadKey(threadKey string) error {
+	if utf8.RuneCountInString(threadKey) > maxThreadKeyLength {
+		return errs.ErrInvalidThreadKey
+	}
 	return nil
 }
@@ -0,0 +1,32 @@
+package impl
+
+import (
+	"Hermes/internal/errs"
+	"Hermes/internal/models"
+	"context"
+	"errors"
+	"strings"
+)
+
+func (s *Service) VoteComment(ctx context.Context, commentID int64, voter string, value int) (models.Comment, error) {
+
+	if strings.TrimSpace(voter) == "" {
+		return models.Comment{}, errs.ErrEmptyVoter
+	}
+
+	if value < -1 || value > 1 {
+		return models.Comment{}, errs.ErrInvalidVote
+	}
+
+	comment, err := s.storage.VoteComment(ctx, commentID, voter, value)
+	if err != nil {
+		if errors.Is(err, errs.ErrCommentNotFound) {
+			return models.Comment{}, err
+		}
+		s.logger.LogError("service — failed to vote on comment", err, "id", commentID, "layer", "service.impl")
+		return models.Comment{}, err
+	}
+
+	return comment, nil
+
+}
@@ -35,33 +35,171 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
-// CreateComment mocks base method.
-func (m *MockService) CreateComment(ctx context.Context, comment models.Comment) (int64, error) {
+// AddReaction mocks base method.
+func (m *MockService) AddReaction(ctx context.Context, commentID int64, emoji, reactor string) (map[string]int, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateComment", ctx, comment)
-	ret0, _ := ret[0].(int64)
+	ret := m.ctrl.Call(m, "AddReaction", ctx, commentID, emoji, reactor)
+	ret0, _ := ret[0].(map[string]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddReaction indicates an expected call of AddReaction.
+func (mr *MockServiceMockRecorder) AddReaction(ctx, commentID, emoji, reactor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReaction", reflect.TypeOf((*MockService)(nil).AddReaction), ctx, commentID, emoji, reactor)
+}
+
+// ApproveComment mocks base method.
+func (m *MockService) ApproveComment(ctx context.Context, id int64) (models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveComment", ctx, id)
+	ret0, _ := ret[0].(models.Comment)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// ApproveComment indicates an expected call of ApproveComment.
+func (mr *MockServiceMockRecorder) ApproveComment(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveComment", reflect.TypeOf((*MockService)(nil).ApproveComment), ctx, id)
+}
+
+// ArchiveThreads mocks base method.
+func (m *MockService) ArchiveThreads(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveThreads", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ArchiveThreads indicates an expected call of ArchiveThreads.
+func (mr *MockServiceMockRecorder) ArchiveThreads(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveThreads", reflect.TypeOf((*MockService)(nil).ArchiveThreads), ctx)
+}
+
+// AuthenticateAPIKey mocks base method.
+func (m *MockService) AuthenticateAPIKey(ctx context.Context, secret string) (models.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AuthenticateAPIKey", ctx, secret)
+	ret0, _ := ret[0].(models.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AuthenticateAPIKey indicates an expected call of AuthenticateAPIKey.
+func (mr *MockServiceMockRecorder) AuthenticateAPIKey(ctx, secret interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AuthenticateAPIKey", reflect.TypeOf((*MockService)(nil).AuthenticateAPIKey), ctx, secret)
+}
+
+// CountComments mocks base method.
+func (m *MockService) CountComments(ctx context.Context, queryParams models.QueryParams) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountComments", ctx, queryParams)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountComments indicates an expected call of CountComments.
+func (mr *MockServiceMockRecorder) CountComments(ctx, queryParams interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountComments", reflect.TypeOf((*MockService)(nil).CountComments), ctx, queryParams)
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockService) CreateAPIKey(ctx context.Context, name string, scopes []string, caller models.Identity) (models.APIKey, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, name, scopes, caller)
+	ret0, _ := ret[0].(models.APIKey)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockServiceMockRecorder) CreateAPIKey(ctx, name, scopes, caller interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockService)(nil).CreateAPIKey), ctx, name, scopes, caller)
+}
+
+// CreateComment mocks base method.
+func (m *MockService) CreateComment(ctx context.Context, comment models.Comment, proof models.Proof) (int64, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateComment", ctx, comment, proof)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
 // CreateComment indicates an expected call of CreateComment.
-func (mr *MockServiceMockRecorder) CreateComment(ctx, comment interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) CreateComment(ctx, comment, proof interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockService)(nil).CreateComment), ctx, comment)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockService)(nil).CreateComment), ctx, comment, proof)
 }
 
 // DeleteComment mocks base method.
-func (m *MockService) DeleteComment(ctx context.Context, id int64) error {
+func (m *MockService) DeleteComment(ctx context.Context, id int64, caller models.Identity) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteComment", ctx, id)
+	ret := m.ctrl.Call(m, "DeleteComment", ctx, id, caller)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteComment indicates an expected call of DeleteComment.
-func (mr *MockServiceMockRecorder) DeleteComment(ctx, id interface{}) *gomock.Call {
+func (mr *MockServiceMockRecorder) DeleteComment(ctx, id, caller interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockService)(nil).DeleteComment), ctx, id, caller)
+}
+
+// GetAncestors mocks base method.
+func (m *MockService) GetAncestors(ctx context.Context, id int64) ([]models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAncestors", ctx, id)
+	ret0, _ := ret[0].([]models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAncestors indicates an expected call of GetAncestors.
+func (mr *MockServiceMockRecorder) GetAncestors(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAncestors", reflect.TypeOf((*MockService)(nil).GetAncestors), ctx, id)
+}
+
+// GetChildren mocks base method.
+func (m *MockService) GetChildren(ctx context.Context, parentID int64, queryParams models.QueryParams) ([]models.Comment, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChildren", ctx, parentID, queryParams)
+	ret0, _ := ret[0].([]models.Comment)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetChildren indicates an expected call of GetChildren.
+func (mr *MockServiceMockRecorder) GetChildren(ctx, parentID, queryParams interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockService)(nil).DeleteComment), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChildren", reflect.TypeOf((*MockService)(nil).GetChildren), ctx, parentID, queryParams)
+}
+
+// GetComment mocks base method.
+func (m *MockService) GetComment(ctx context.Context, id int64) (models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetComment", ctx, id)
+	ret0, _ := ret[0].(models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetComment indicates an expected call of GetComment.
+func (mr *MockServiceMockRecorder) GetComment(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComment", reflect.TypeOf((*MockService)(nil).GetComment), ctx, id)
 }
 
 // GetComments mocks base method.
@@ -78,3 +216,275 @@ func (mr *MockServiceMockRecorder) GetComments(ctx, queryParams interface{}) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetComments", reflect.TypeOf((*MockService)(nil).GetComments), ctx, queryParams)
 }
+
+// GetPendingComments mocks base method.
+func (m *MockService) GetPendingComments(ctx context.Context, queryParams models.QueryParams) ([]models.Comment, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingComments", ctx, queryParams)
+	ret0, _ := ret[0].([]models.Comment)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetPendingComments indicates an expected call of GetPendingComments.
+func (mr *MockServiceMockRecorder) GetPendingComments(ctx, queryParams interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingComments", reflect.TypeOf((*MockService)(nil).GetPendingComments), ctx, queryParams)
+}
+
+// GetReportedComments mocks base method.
+func (m *MockService) GetReportedComments(ctx context.Context, queryParams models.QueryParams) ([]models.ReportedComment, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportedComments", ctx, queryParams)
+	ret0, _ := ret[0].([]models.ReportedComment)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetReportedComments indicates an expected call of GetReportedComments.
+func (mr *MockServiceMockRecorder) GetReportedComments(ctx, queryParams interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportedComments", reflect.TypeOf((*MockService)(nil).GetReportedComments), ctx, queryParams)
+}
+
+// GetRevisions mocks base method.
+func (m *MockService) GetRevisions(ctx context.Context, commentID int64) ([]models.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRevisions", ctx, commentID)
+	ret0, _ := ret[0].([]models.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRevisions indicates an expected call of GetRevisions.
+func (mr *MockServiceMockRecorder) GetRevisions(ctx, commentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRevisions", reflect.TypeOf((*MockService)(nil).GetRevisions), ctx, commentID)
+}
+
+// IssueChallenge mocks base method.
+func (m *MockService) IssueChallenge(ctx context.Context) (models.Challenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueChallenge", ctx)
+	ret0, _ := ret[0].(models.Challenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueChallenge indicates an expected call of IssueChallenge.
+func (mr *MockServiceMockRecorder) IssueChallenge(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueChallenge", reflect.TypeOf((*MockService)(nil).IssueChallenge), ctx)
+}
+
+// ListAPIKeys mocks base method.
+func (m *MockService) ListAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAPIKeys", ctx)
+	ret0, _ := ret[0].([]models.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAPIKeys indicates an expected call of ListAPIKeys.
+func (mr *MockServiceMockRecorder) ListAPIKeys(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAPIKeys", reflect.TypeOf((*MockService)(nil).ListAPIKeys), ctx)
+}
+
+// LockComment mocks base method.
+func (m *MockService) LockComment(ctx context.Context, id int64) (models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockComment", ctx, id)
+	ret0, _ := ret[0].(models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockComment indicates an expected call of LockComment.
+func (mr *MockServiceMockRecorder) LockComment(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockComment", reflect.TypeOf((*MockService)(nil).LockComment), ctx, id)
+}
+
+// MoveComment mocks base method.
+func (m *MockService) MoveComment(ctx context.Context, id int64, parentID *int64) (models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveComment", ctx, id, parentID)
+	ret0, _ := ret[0].(models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveComment indicates an expected call of MoveComment.
+func (mr *MockServiceMockRecorder) MoveComment(ctx, id, parentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveComment", reflect.TypeOf((*MockService)(nil).MoveComment), ctx, id, parentID)
+}
+
+// PinComment mocks base method.
+func (m *MockService) PinComment(ctx context.Context, id int64) (models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PinComment", ctx, id)
+	ret0, _ := ret[0].(models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PinComment indicates an expected call of PinComment.
+func (mr *MockServiceMockRecorder) PinComment(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PinComment", reflect.TypeOf((*MockService)(nil).PinComment), ctx, id)
+}
+
+// RejectComment mocks base method.
+func (m *MockService) RejectComment(ctx context.Context, id int64) (models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectComment", ctx, id)
+	ret0, _ := ret[0].(models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RejectComment indicates an expected call of RejectComment.
+func (mr *MockServiceMockRecorder) RejectComment(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectComment", reflect.TypeOf((*MockService)(nil).RejectComment), ctx, id)
+}
+
+// RemoveReaction mocks base method.
+func (m *MockService) RemoveReaction(ctx context.Context, commentID int64, emoji, reactor string) (map[string]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveReaction", ctx, commentID, emoji, reactor)
+	ret0, _ := ret[0].(map[string]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveReaction indicates an expected call of RemoveReaction.
+func (mr *MockServiceMockRecorder) RemoveReaction(ctx, commentID, emoji, reactor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveReaction", reflect.TypeOf((*MockService)(nil).RemoveReaction), ctx, commentID, emoji, reactor)
+}
+
+// ReportComment mocks base method.
+func (m *MockService) ReportComment(ctx context.Context, report models.Report) (models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportComment", ctx, report)
+	ret0, _ := ret[0].(models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReportComment indicates an expected call of ReportComment.
+func (mr *MockServiceMockRecorder) ReportComment(ctx, report interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportComment", reflect.TypeOf((*MockService)(nil).ReportComment), ctx, report)
+}
+
+// ResolveRoles mocks base method.
+func (m *MockService) ResolveRoles(ctx context.Context, identity models.Identity) (models.Identity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveRoles", ctx, identity)
+	ret0, _ := ret[0].(models.Identity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveRoles indicates an expected call of ResolveRoles.
+func (mr *MockServiceMockRecorder) ResolveRoles(ctx, identity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveRoles", reflect.TypeOf((*MockService)(nil).ResolveRoles), ctx, identity)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockService) RevokeAPIKey(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockServiceMockRecorder) RevokeAPIKey(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockService)(nil).RevokeAPIKey), ctx, id)
+}
+
+// SearchComments mocks base method.
+func (m *MockService) SearchComments(ctx context.Context, searchParams models.SearchParams) ([]models.SearchResult, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchComments", ctx, searchParams)
+	ret0, _ := ret[0].([]models.SearchResult)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchComments indicates an expected call of SearchComments.
+func (mr *MockServiceMockRecorder) SearchComments(ctx, searchParams interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchComments", reflect.TypeOf((*MockService)(nil).SearchComments), ctx, searchParams)
+}
+
+// UnlockComment mocks base method.
+func (m *MockService) UnlockComment(ctx context.Context, id int64) (models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockComment", ctx, id)
+	ret0, _ := ret[0].(models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnlockComment indicates an expected call of UnlockComment.
+func (mr *MockServiceMockRecorder) UnlockComment(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockComment", reflect.TypeOf((*MockService)(nil).UnlockComment), ctx, id)
+}
+
+// UnpinComment mocks base method.
+func (m *MockService) UnpinComment(ctx context.Context, id int64) (models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnpinComment", ctx, id)
+	ret0, _ := ret[0].(models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnpinComment indicates an expected call of UnpinComment.
+func (mr *MockServiceMockRecorder) UnpinComment(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnpinComment", reflect.TypeOf((*MockService)(nil).UnpinComment), ctx, id)
+}
+
+// UpdateComment mocks base method.
+func (m *MockService) UpdateComment(ctx context.Context, comment models.Comment, caller models.Identity) (models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateComment", ctx, comment, caller)
+	ret0, _ := ret[0].(models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateComment indicates an expected call of UpdateComment.
+func (mr *MockServiceMockRecorder) UpdateComment(ctx, comment, caller interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateComment", reflect.TypeOf((*MockService)(nil).UpdateComment), ctx, comment, caller)
+}
+
+// VoteComment mocks base method.
+func (m *MockService) VoteComment(ctx context.Context, commentID int64, voter string, value int) (models.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoteComment", ctx, commentID, voter, value)
+	ret0, _ := ret[0].(models.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoteComment indicates an expected call of VoteComment.
+func (mr *MockServiceMockRecorder) VoteComment(ctx, commentID, voter, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoteComment", reflect.TypeOf((*MockService)(nil).VoteComment), ctx, commentID, voter, value)
+}
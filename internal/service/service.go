@@ -1,6 +1,8 @@
 package service
 
 import (
+	"Hermes/internal/config"
+	"Hermes/internal/filter"
 	"Hermes/internal/logger"
 	"Hermes/internal/models"
 	"Hermes/internal/repository"
@@ -9,11 +11,38 @@ import (
 )
 
 type Service interface {
-	CreateComment(ctx context.Context, comment models.Comment) (int64, error)
+	IssueChallenge(ctx context.Context) (models.Challenge, error)
+	CreateComment(ctx context.Context, comment models.Comment, proof models.Proof) (int64, string, error)
+	GetComment(ctx context.Context, id int64) (models.Comment, error)
+	GetAncestors(ctx context.Context, id int64) ([]models.Comment, error)
 	GetComments(ctx context.Context, queryParams models.QueryParams) ([]models.Comment, error)
-	DeleteComment(ctx context.Context, id int64) error
+	CountComments(ctx context.Context, queryParams models.QueryParams) (int, error)
+	GetChildren(ctx context.Context, parentID int64, queryParams models.QueryParams) ([]models.Comment, int, error)
+	UpdateComment(ctx context.Context, comment models.Comment, caller models.Identity) (models.Comment, error)
+	GetRevisions(ctx context.Context, commentID int64) ([]models.Revision, error)
+	VoteComment(ctx context.Context, commentID int64, voter string, value int) (models.Comment, error)
+	AddReaction(ctx context.Context, commentID int64, emoji, reactor string) (map[string]int, error)
+	RemoveReaction(ctx context.Context, commentID int64, emoji, reactor string) (map[string]int, error)
+	SearchComments(ctx context.Context, searchParams models.SearchParams) ([]models.SearchResult, int, error)
+	DeleteComment(ctx context.Context, id int64, caller models.Identity) error
+	GetPendingComments(ctx context.Context, queryParams models.QueryParams) ([]models.Comment, int, error)
+	ApproveComment(ctx context.Context, id int64) (models.Comment, error)
+	RejectComment(ctx context.Context, id int64) (models.Comment, error)
+	LockComment(ctx context.Context, id int64) (models.Comment, error)
+	PinComment(ctx context.Context, id int64) (models.Comment, error)
+	UnpinComment(ctx context.Context, id int64) (models.Comment, error)
+	UnlockComment(ctx context.Context, id int64) (models.Comment, error)
+	MoveComment(ctx context.Context, id int64, parentID *int64) (models.Comment, error)
+	ArchiveThreads(ctx context.Context) (int, error)
+	ReportComment(ctx context.Context, report models.Report) (models.Comment, error)
+	GetReportedComments(ctx context.Context, queryParams models.QueryParams) ([]models.ReportedComment, int, error)
+	ResolveRoles(ctx context.Context, identity models.Identity) (models.Identity, error)
+	AuthenticateAPIKey(ctx context.Context, secret string) (models.Identity, error)
+	CreateAPIKey(ctx context.Context, name string, scopes []string, caller models.Identity) (models.APIKey, string, error)
+	ListAPIKeys(ctx context.Context) ([]models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id int64) error
 }
 
-func NewService(logger logger.Logger, storage repository.Storage) Service {
-	return impl.NewService(logger, storage)
+func NewService(logger logger.Logger, config config.Comments, storage repository.Storage, filters []filter.ContentFilter) Service {
+	return impl.NewService(logger, config, storage, filters)
 }
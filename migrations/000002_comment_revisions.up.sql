@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS comment_revisions (
+    id         INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    comment_id INTEGER NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    content    TEXT NOT NULL,
+    author     VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_revisions_comment_id ON comment_revisions (comment_id, id);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_comments_roots_created_at_id;
\ No newline at end of file
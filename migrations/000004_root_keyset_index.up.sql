@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_comments_roots_created_at_id ON comments (created_at, id) WHERE parent_id IS NULL;
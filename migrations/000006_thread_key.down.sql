@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_comments_roots_thread_key_created_at_id;
+CREATE INDEX IF NOT EXISTS idx_comments_roots_created_at_id ON comments (created_at, id) WHERE parent_id IS NULL;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS thread_key;
\ No newline at end of file
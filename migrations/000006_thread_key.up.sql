@@ -0,0 +1,4 @@
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS thread_key VARCHAR(255) NOT NULL DEFAULT '';
+
+DROP INDEX IF EXISTS idx_comments_roots_created_at_id;
+CREATE INDEX IF NOT EXISTS idx_comments_roots_thread_key_created_at_id ON comments (thread_key, created_at, id) WHERE parent_id IS NULL;
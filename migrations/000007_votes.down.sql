@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_comments_roots_thread_key_score;
+DROP TABLE IF EXISTS comment_votes;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS downvotes;
+ALTER TABLE comments DROP COLUMN IF EXISTS upvotes;
\ No newline at end of file
@@ -0,0 +1,13 @@
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS upvotes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS downvotes INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS comment_votes (
+    comment_id INTEGER NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    voter      VARCHAR(255) NOT NULL,
+    value      SMALLINT NOT NULL CHECK (value IN (-1, 1)),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (comment_id, voter)
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_roots_thread_key_score ON comments (thread_key, (upvotes - downvotes) DESC, created_at DESC) WHERE parent_id IS NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_comments_roots_thread_key_hot_score;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS hot_score;
+ALTER TABLE comments DROP COLUMN IF EXISTS reply_count;
\ No newline at end of file
@@ -0,0 +1,19 @@
+-- reply_count counts every reply a comment has received; like votes it is
+-- activity, so deleting a reply later does not take it back.
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS reply_count INTEGER NOT NULL DEFAULT 0;
+
+UPDATE comments c
+SET reply_count = (SELECT COUNT(*) FROM comments r WHERE r.parent_id = c.id);
+
+-- hot_score follows Reddit's ranking: the order of magnitude of a comment's
+-- activity plus its age, where 45000 seconds (12.5 hours) of recency is worth
+-- ten times the activity. It is recomputed whenever a vote or reply changes
+-- the row, so sort=hot is paged by offset and a page may repeat or skip a
+-- comment whose score moved between two requests.
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS hot_score DOUBLE PRECISION
+    GENERATED ALWAYS AS (
+        SIGN(upvotes - downvotes + reply_count)::float * LOG(GREATEST(ABS(upvotes - downvotes + reply_count), 1)::float)
+        + EXTRACT(EPOCH FROM created_at)::float / 45000
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_comments_roots_thread_key_hot_score ON comments (thread_key, hot_score DESC, id DESC) WHERE parent_id IS NULL;
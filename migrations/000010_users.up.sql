@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS users (
+    subject    VARCHAR(255) PRIMARY KEY,
+    role       VARCHAR(32) NOT NULL CHECK (role IN ('reader', 'commenter', 'moderator', 'admin')),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
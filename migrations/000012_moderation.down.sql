@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_comments_pending_created_at;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS status;
\ No newline at end of file
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS comment_reports;
+
+UPDATE comments SET status = 'pending' WHERE status = 'hidden';
+
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS comments_status_check;
+ALTER TABLE comments ADD CONSTRAINT comments_status_check
+    CHECK (status IN ('pending', 'approved', 'rejected'));
\ No newline at end of file
@@ -0,0 +1,13 @@
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS comments_status_check;
+ALTER TABLE comments ADD CONSTRAINT comments_status_check
+    CHECK (status IN ('pending', 'approved', 'rejected', 'hidden'));
+
+CREATE TABLE IF NOT EXISTS comment_reports (
+    comment_id INTEGER NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    reporter   VARCHAR(255) NOT NULL,
+    reason     VARCHAR(32) NOT NULL
+        CHECK (reason IN ('spam', 'harassment', 'hate-speech', 'off-topic', 'other')),
+    note       TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (comment_id, reporter)
+);
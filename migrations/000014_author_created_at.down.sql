@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_comments_author_created_at;
\ No newline at end of file
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_comments_author_created_at ON comments (author, created_at DESC);
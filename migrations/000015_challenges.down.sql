@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_comments_created_at;
+
+DROP TABLE IF EXISTS used_challenges;
\ No newline at end of file
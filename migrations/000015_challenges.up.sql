@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS used_challenges (
+    id         CHAR(32) PRIMARY KEY,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_used_challenges_expires_at ON used_challenges (expires_at);
+CREATE INDEX IF NOT EXISTS idx_comments_created_at ON comments (created_at);
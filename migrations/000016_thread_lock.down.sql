@@ -0,0 +1 @@
+ALTER TABLE comments DROP COLUMN IF EXISTS locked_at;
\ No newline at end of file
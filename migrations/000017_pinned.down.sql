@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_comments_pinned_at;
+
+ALTER TABLE comments DROP COLUMN IF EXISTS pinned_at;
\ No newline at end of file
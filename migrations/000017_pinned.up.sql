@@ -0,0 +1,3 @@
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS pinned_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_comments_pinned_at ON comments (thread_key, pinned_at DESC) WHERE pinned_at IS NOT NULL;
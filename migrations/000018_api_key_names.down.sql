@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_api_keys_active_name;
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- A key's name is the subject its comments are posted under, so no two
+-- live keys may share one. A revoked key's name can be reused by the key
+-- replacing it.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_active_name ON api_keys (name) WHERE revoked_at IS NULL;
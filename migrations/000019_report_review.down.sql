@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_comment_reports_open;
+
+ALTER TABLE comment_reports DROP COLUMN IF EXISTS reviewed_at;
\ No newline at end of file
@@ -0,0 +1,6 @@
+-- reviewed_at marks reports a moderator has dealt with by approving or
+-- rejecting the comment. Only open reports are listed and count towards
+-- hiding a comment.
+ALTER TABLE comment_reports ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_comment_reports_open ON comment_reports (comment_id) WHERE reviewed_at IS NULL;
@@ -1,6 +1,10 @@
 const API_BASE = "/api/v1/comments";
+const CHALLENGE_URL = "/api/v1/challenge";
+const TOKEN_KEY = "hermes.token";
+const AUTHOR_TOKEN_KEY = "hermes.author.";
 
 const state = {
+  threadKey: new URLSearchParams(window.location.search).get("thread_key") || "",
   page: 1,
   limit: 10,
   sort: "created_at_desc",
@@ -20,6 +24,9 @@ const authorInput = document.getElementById("authorInput");
 const contentInput = document.getElementById("contentInput");
 const createTopBtn = document.getElementById("createTopBtn");
 const msg = document.getElementById("msg");
+const tokenInput = document.getElementById("tokenInput");
+
+tokenInput.value = localStorage.getItem(TOKEN_KEY) || "";
 
 function showMessage(text, isError) {
   msg.textContent = text || "";
@@ -30,7 +37,12 @@ function showMessage(text, isError) {
     }, 4000);
 }
 
-async function fetchJSON(url, opts = {}) {
+async function fetchJSON(url, opts = {}, envelope = false) {
+  const token = tokenInput.value.trim();
+  if (token) {
+    opts = { ...opts, headers: { ...opts.headers, Authorization: "Bearer " + token } };
+  }
+
   const res = await fetch(url, opts);
   const text = await res.text();
   let data = null;
@@ -46,6 +58,13 @@ async function fetchJSON(url, opts = {}) {
     throw new Error(errMsg);
   }
 
+  // Anonymous authors get a token proving they wrote the new comment.
+  const authorToken = res.headers.get("X-Author-Token");
+  if (authorToken && data && data.result !== undefined) {
+    localStorage.setItem(AUTHOR_TOKEN_KEY + data.result, authorToken);
+  }
+
+  if (envelope) return data;
   return data && data.result !== undefined ? data.result : data;
 }
 
@@ -54,17 +73,19 @@ async function loadComments() {
 
   try {
     if (state.searching && state.searchQuery.trim() !== "") {
-      const limit = 100;
-      const url = `${API_BASE}?page=1&limit=${limit}&sort=${state.sort}`;
-      const roots = await fetchJSON(url);
-      const filtered = filterForestByQuery(roots, state.searchQuery);
-      renderComments(filtered);
-      pageInfo.textContent = `Search: "${state.searchQuery}" — ${countNodes(filtered)} results`;
+      const q = encodeURIComponent(state.searchQuery);
+      const url = `${API_BASE}/search?q=${q}&page=${state.page}&limit=${state.limit}&${threadQuery()}`;
+      const page = await fetchJSON(url, {}, true);
+      renderSearchResults(page.result);
+      pageInfo.textContent = `Search: "${state.searchQuery}" — ${page.total} results`;
+      setPagerEnabled(page.page > 1, page.has_more);
     } else {
-      const url = `${API_BASE}?page=${state.page}&limit=${state.limit}&sort=${state.sort}`;
-      const roots = await fetchJSON(url);
-      renderComments(roots);
-      pageInfo.textContent = `Page ${state.page}`;
+      const url = `${API_BASE}?page=${state.page}&limit=${state.limit}&sort=${state.sort}&${threadQuery()}`;
+      const page = await fetchJSON(url, {}, true);
+      renderComments(page.result);
+      const pages = Math.max(1, Math.ceil(page.total / page.limit));
+      pageInfo.textContent = `Page ${page.page} of ${pages} · ${page.total} comments`;
+      setPagerEnabled(page.page > 1, page.has_more);
     }
   } catch (err) {
     commentsRoot.innerHTML = "";
@@ -72,34 +93,13 @@ async function loadComments() {
   }
 }
 
-function countNodes(list) {
-  let cnt = 0;
-  function walk(node) {
-    cnt++;
-    (node.children || []).forEach(walk);
-  }
-  list.forEach(walk);
-  return cnt;
+function threadQuery() {
+  return `thread_key=${encodeURIComponent(state.threadKey)}`;
 }
 
-function filterForestByQuery(list, q) {
-  const ql = q.trim().toLowerCase();
-  if (!ql) return list;
-
-  function filterNode(node) {
-    const me =
-      (node.content || "").toLowerCase().includes(ql) ||
-      (node.author || "").toLowerCase().includes(ql);
-
-    const children = (node.children || []).map(filterNode).filter(Boolean);
-
-    if (me || children.length) {
-      return { ...node, children };
-    }
-    return null;
-  }
-
-  return list.map(filterNode).filter(Boolean);
+function setPagerEnabled(prev, next) {
+  prevBtn.disabled = !prev;
+  nextBtn.disabled = !next;
 }
 
 function renderComments(list) {
@@ -116,6 +116,35 @@ function renderComments(list) {
   });
 }
 
+function renderSearchResults(list) {
+  commentsRoot.innerHTML = "";
+
+  if (!list || list.length === 0) {
+    commentsRoot.innerHTML = '<div class="small">Nothing found</div>';
+    return;
+  }
+
+  list.forEach((r) => {
+    const el = renderNode({ ...r, children: [] }, 0);
+    const ancestors = r.ancestors || [];
+
+    if (ancestors.length) {
+      const context = document.createElement("div");
+      context.className = "small";
+      context.textContent = `In reply to #${ancestors[ancestors.length - 1]} · thread #${ancestors[0]}`;
+      el.insertBefore(context, el.firstChild);
+    }
+
+    const jumpBtn = document.createElement("button");
+    jumpBtn.className = "ghost";
+    jumpBtn.textContent = "Go to thread";
+    jumpBtn.onclick = () => openThread(ancestors.length ? ancestors[0] : r.id);
+    el.querySelector(".actions").appendChild(jumpBtn);
+
+    commentsRoot.appendChild(el);
+  });
+}
+
 function renderNode(node, level) {
   const wrap = document.createElement("div");
   wrap.style.marginLeft = level * 16 + "px";
@@ -153,7 +182,11 @@ function renderNode(node, level) {
   delBtn.onclick = async () => {
     if (!confirm("Delete this comment and all nested replies?")) return;
     try {
-      await fetchJSON(`${API_BASE}/${node.id}`, { method: "DELETE" });
+      const authorToken = localStorage.getItem(AUTHOR_TOKEN_KEY + node.id);
+      await fetchJSON(`${API_BASE}/${node.id}`, {
+        method: "DELETE",
+        headers: authorToken ? { "X-Author-Token": authorToken } : {},
+      });
       showMessage("Deleted");
       await loadComments();
     } catch (err) {
@@ -205,11 +238,7 @@ function openReplyForm(container, parentID) {
       content: ta.value,
     };
     try {
-      await fetchJSON(API_BASE, {
-        method: "POST",
-        headers: { "Content-Type": "application/json" },
-        body: JSON.stringify(payload),
-      });
+      await postComment(payload);
       showMessage("Reply added");
       await loadComments();
     } catch (err) {
@@ -229,10 +258,11 @@ function openReplyForm(container, parentID) {
 async function openThread(id) {
   commentsRoot.innerHTML = '<div class="small">Loading thread...</div>';
   try {
-    const url = `${API_BASE}?parent=${id}`;
+    const url = `${API_BASE}?parent=${id}&${threadQuery()}`;
     const roots = await fetchJSON(url);
     renderComments(roots);
     pageInfo.textContent = `Thread ${id}`;
+    setPagerEnabled(false, false);
   } catch (err) {
     showMessage(err.message, true);
   }
@@ -242,7 +272,7 @@ async function createTopComment() {
   const author = authorInput.value.trim();
   const content = contentInput.value.trim();
 
-  if (!author) {
+  if (!author && !tokenInput.value.trim()) {
     showMessage("Author is required", true);
     return;
   }
@@ -252,14 +282,10 @@ async function createTopComment() {
     return;
   }
 
-  const payload = { parent_id: null, author, content };
+  const payload = { parent_id: null, thread_key: state.threadKey, author, content };
 
   try {
-    await fetchJSON(API_BASE, {
-      method: "POST",
-      headers: { "Content-Type": "application/json" },
-      body: JSON.stringify(payload),
-    });
+    await postComment(payload);
 
     authorInput.value = "";
     contentInput.value = "";
@@ -270,6 +296,49 @@ async function createTopComment() {
   }
 }
 
+// postComment creates a comment. Anonymous callers first fetch a
+// proof-of-work challenge and send its solution along, if the server asks.
+async function postComment(payload) {
+  if (!tokenInput.value.trim()) {
+    const pow = await fetchJSON(CHALLENGE_URL);
+    if (pow && pow.required) {
+      showMessage("Solving anti-spam challenge...");
+      const nonce = await solveChallenge(pow.challenge, pow.difficulty);
+      payload = { ...payload, challenge: pow.challenge, nonce };
+    }
+  }
+
+  return fetchJSON(API_BASE, {
+    method: "POST",
+    headers: { "Content-Type": "application/json" },
+    body: JSON.stringify(payload),
+  });
+}
+
+// solveChallenge finds a nonce such that SHA-256(challenge + ":" + nonce)
+// starts with difficulty zero bits.
+async function solveChallenge(challenge, difficulty) {
+  const encoder = new TextEncoder();
+  for (let nonce = 0; ; nonce++) {
+    const digest = await crypto.subtle.digest(
+      "SHA-256",
+      encoder.encode(challenge + ":" + nonce),
+    );
+    if (leadingZeroBits(new Uint8Array(digest)) >= difficulty) {
+      return String(nonce);
+    }
+  }
+}
+
+function leadingZeroBits(bytes) {
+  let n = 0;
+  for (const b of bytes) {
+    if (b !== 0) return n + Math.clz32(b) - 24;
+    n += 8;
+  }
+  return n;
+}
+
 function formatDate(iso) {
   try {
     if (!iso) return "";
@@ -296,6 +365,7 @@ searchBtn.addEventListener("click", () => {
   const q = searchInput.value.trim();
   state.searching = !!q;
   state.searchQuery = q;
+  state.page = 1;
   loadComments();
 });
 
@@ -324,4 +394,9 @@ nextBtn.addEventListener("click", () => {
 
 createTopBtn.addEventListener("click", createTopComment);
 
+tokenInput.addEventListener("change", () => {
+  localStorage.setItem(TOKEN_KEY, tokenInput.value.trim());
+  loadComments();
+});
+
 loadComments();